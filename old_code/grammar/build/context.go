@@ -0,0 +1,69 @@
+package build
+
+// BuildContext configures how build constraints are evaluated: the
+// set of active build tags plus the implicit goos/goarch/cgo tags
+// cmd/go always adds.
+type BuildContext struct {
+	Tags       []string
+	GOOS       string
+	GOARCH     string
+	CgoEnabled bool
+}
+
+// tagSet expands a BuildContext into the map Expr.Eval expects,
+// adding the implicit tags cmd/go derives from GOOS/GOARCH/cgo.
+func (c *BuildContext) tagSet() map[string]bool {
+	tags := map[string]bool{
+		c.GOOS:   true,
+		c.GOARCH: true,
+		"unix":   isUnix(c.GOOS),
+	}
+	if c.CgoEnabled {
+		tags["cgo"] = true
+	}
+	for _, t := range c.Tags {
+		tags[t] = true
+	}
+	return tags
+}
+
+func isUnix(goos string) bool {
+	switch goos {
+	case "aix", "android", "darwin", "dragonfly", "freebsd", "hurd", "illumos",
+		"ios", "linux", "netbsd", "openbsd", "solaris":
+		return true
+	}
+	return false
+}
+
+// Eval reports whether constraint is satisfied under c. A nil
+// constraint (a file with no //go:build or +build line) is always
+// satisfied.
+func (c *BuildContext) Eval(constraint *BuildConstraint) bool {
+	if constraint == nil {
+		return true
+	}
+	return constraint.Expr.Eval(c.tagSet())
+}
+
+// File is one source file's build-relevant metadata, as collected by
+// the lexer pass that recognizes go: pragma comments.
+type File struct {
+	Name       string
+	Constraint *BuildConstraint
+	Generate   []*GenerateDirective
+	// Skipped is set by FilterFiles when Constraint evaluates to
+	// false under the active BuildContext.
+	Skipped bool
+}
+
+// FilterFiles evaluates each file's build constraint under c,
+// marking files whose constraint is not satisfied as Skipped so
+// their decls can be excluded from the package AST, and returns the
+// same slice for convenience.
+func FilterFiles(files []*File, c *BuildContext) []*File {
+	for _, f := range files {
+		f.Skipped = !c.Eval(f.Constraint)
+	}
+	return files
+}
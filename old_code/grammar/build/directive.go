@@ -0,0 +1,116 @@
+package build
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+)
+
+// BuildConstraint is a parsed "//go:build" line (or, for files that
+// predate it, the equivalent "// +build" line).
+type BuildConstraint struct {
+	Pos  token.Pos
+	Expr Expr
+}
+
+// ParseBuildConstraint parses a single comment line's text (with the
+// "//go:build" or "// +build" prefix already identified by the
+// lexer) into a BuildConstraint.
+func ParseBuildConstraint(pos token.Pos, line string, legacy bool) (*BuildConstraint, error) {
+	var (
+		expr Expr
+		err  error
+	)
+	if legacy {
+		expr, err = ParseLegacyExpr(strings.TrimPrefix(strings.TrimSpace(line), "+build"))
+	} else {
+		expr, err = ParseExpr(strings.TrimSpace(strings.TrimPrefix(line, "go:build")))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &BuildConstraint{Pos: pos, Expr: expr}, nil
+}
+
+// GenerateDirective is a parsed "//go:generate" line.
+type GenerateDirective struct {
+	Pos token.Pos
+	// Argv is the tokenized command line, following the same
+	// quoting rules as the shell: a backslash escapes the next
+	// character and single/double quotes group arguments containing
+	// spaces.
+	Argv []string
+	// IsCommandAlias is set when this directive defines a "-command" alias
+	// rather than invoking one: //go:generate -command foo bar baz.
+	IsCommandAlias bool
+}
+
+// ParseGenerateDirective parses a //go:generate line's argument text
+// (with the "go:generate" prefix already stripped) into argv,
+// expanding none of the $GOFILE/$GOPACKAGE variables so callers can
+// still see and substitute them.
+func ParseGenerateDirective(pos token.Pos, line string) (*GenerateDirective, error) {
+	argv, err := splitArgv(strings.TrimSpace(line))
+	if err != nil {
+		return nil, err
+	}
+	if len(argv) == 0 {
+		return nil, fmt.Errorf("build: empty go:generate directive")
+	}
+	d := &GenerateDirective{Pos: pos, Argv: argv}
+	if argv[0] == "-command" {
+		d.IsCommandAlias = true
+	}
+	return d, nil
+}
+
+func splitArgv(s string) ([]string, error) {
+	var (
+		argv    []string
+		cur     strings.Builder
+		inQuote byte
+		have    bool
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case inQuote != 0:
+			if c == inQuote {
+				inQuote = 0
+			} else {
+				cur.WriteByte(c)
+			}
+		case c == '\\' && i+1 < len(s):
+			i++
+			cur.WriteByte(s[i])
+			have = true
+		case c == '\'' || c == '"':
+			inQuote = c
+			have = true
+		case c == ' ' || c == '\t':
+			if have {
+				argv = append(argv, cur.String())
+				cur.Reset()
+				have = false
+			}
+		default:
+			cur.WriteByte(c)
+			have = true
+		}
+	}
+	if inQuote != 0 {
+		return nil, fmt.Errorf("build: unterminated quote in go:generate directive")
+	}
+	if have {
+		argv = append(argv, cur.String())
+	}
+	return argv, nil
+}
+
+// ExpandVars substitutes $GOFILE and $GOPACKAGE in one argv element,
+// leaving every other $VAR reference untouched so downstream tools
+// (which may support additional variables) still see them.
+func ExpandVars(arg, goFile, goPackage string) string {
+	r := strings.NewReplacer("$GOFILE", goFile, "$GOPACKAGE", goPackage)
+	return r.Replace(arg)
+}
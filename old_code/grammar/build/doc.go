@@ -0,0 +1,10 @@
+// Package build parses //go:build constraints and //go:generate
+// directives out of the comments the main Go grammar already
+// tokenizes, and evaluates build constraints against a BuildContext
+// so callers can decide which files belong in a package.
+//
+// It mirrors cmd/go's own constraint handling closely enough that a
+// constraint written against the real toolchain parses the same way
+// here, including the legacy "// +build" fallback syntax that
+// predates //go:build.
+package build
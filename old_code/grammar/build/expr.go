@@ -0,0 +1,194 @@
+package build
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Expr is a boolean expression over build tags, as found in a
+// //go:build line or a legacy // +build line.
+type Expr interface {
+	Eval(tags map[string]bool) bool
+	String() string
+}
+
+// Tag is a leaf build-tag identifier, such as "unix" or "go1.21".
+type Tag string
+
+func (t Tag) Eval(tags map[string]bool) bool { return tags[string(t)] }
+func (t Tag) String() string                 { return string(t) }
+
+// Not negates its operand: "!linux".
+type Not struct{ X Expr }
+
+func (n *Not) Eval(tags map[string]bool) bool { return !n.X.Eval(tags) }
+func (n *Not) String() string                 { return "!" + parenIfNeeded(n.X) }
+
+// And is the conjunction of two expressions: "linux && amd64".
+type And struct{ X, Y Expr }
+
+func (a *And) Eval(tags map[string]bool) bool { return a.X.Eval(tags) && a.Y.Eval(tags) }
+func (a *And) String() string                 { return parenIfNeeded(a.X) + " && " + parenIfNeeded(a.Y) }
+
+// Or is the disjunction of two expressions: "linux || darwin".
+type Or struct{ X, Y Expr }
+
+func (o *Or) Eval(tags map[string]bool) bool { return o.X.Eval(tags) || o.Y.Eval(tags) }
+func (o *Or) String() string                 { return parenIfNeeded(o.X) + " || " + parenIfNeeded(o.Y) }
+
+func parenIfNeeded(e Expr) string {
+	switch e.(type) {
+	case *And, *Or:
+		return "(" + e.String() + ")"
+	}
+	return e.String()
+}
+
+// ParseExpr parses the boolean expression following "//go:build",
+// supporting &&, ||, !, and parenthesization with the same
+// precedence as cmd/go's constraint.Expr: ! binds tightest, then
+// &&, then ||.
+func ParseExpr(s string) (Expr, error) {
+	p := &exprParser{toks: tokenizeExpr(s)}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.toks) {
+		return nil, fmt.Errorf("build: unexpected token %q", p.toks[p.pos])
+	}
+	return e, nil
+}
+
+func tokenizeExpr(s string) []string {
+	var toks []string
+	i := 0
+	for i < len(s) {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(' || c == ')' || c == '!':
+			toks = append(toks, string(c))
+			i++
+		case strings.HasPrefix(s[i:], "&&"):
+			toks = append(toks, "&&")
+			i += 2
+		case strings.HasPrefix(s[i:], "||"):
+			toks = append(toks, "||")
+			i += 2
+		default:
+			j := i
+			for j < len(s) && s[j] != ' ' && s[j] != '\t' && s[j] != '(' && s[j] != ')' && s[j] != '!' &&
+				!strings.HasPrefix(s[j:], "&&") && !strings.HasPrefix(s[j:], "||") {
+				j++
+			}
+			toks = append(toks, s[i:j])
+			i = j
+		}
+	}
+	return toks
+}
+
+type exprParser struct {
+	toks []string
+	pos  int
+}
+
+func (p *exprParser) peek() string {
+	if p.pos >= len(p.toks) {
+		return ""
+	}
+	return p.toks[p.pos]
+}
+
+func (p *exprParser) parseOr() (Expr, error) {
+	x, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "||" {
+		p.pos++
+		y, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		x = &Or{X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *exprParser) parseAnd() (Expr, error) {
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "&&" {
+		p.pos++
+		y, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		x = &And{X: x, Y: y}
+	}
+	return x, nil
+}
+
+func (p *exprParser) parseUnary() (Expr, error) {
+	if p.peek() == "!" {
+		p.pos++
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{X: x}, nil
+	}
+	if p.peek() == "(" {
+		p.pos++
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("build: missing close paren")
+		}
+		p.pos++
+		return x, nil
+	}
+	tok := p.peek()
+	if tok == "" || tok == "&&" || tok == "||" || tok == ")" {
+		return nil, fmt.Errorf("build: expected build tag, found %q", tok)
+	}
+	p.pos++
+	return Tag(tok), nil
+}
+
+// ParseLegacyExpr parses the older "// +build a,b c" form: a
+// space-separated sequence of OR'd terms, each term a comma-separated
+// sequence of AND'd tags that may be negated with a leading "!".
+func ParseLegacyExpr(s string) (Expr, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("build: empty +build line")
+	}
+	var or Expr
+	for _, term := range fields {
+		var and Expr
+		for _, tag := range strings.Split(term, ",") {
+			var t Expr = Tag(strings.TrimPrefix(tag, "!"))
+			if strings.HasPrefix(tag, "!") {
+				t = &Not{X: Tag(strings.TrimPrefix(tag, "!"))}
+			}
+			if and == nil {
+				and = t
+			} else {
+				and = &And{X: and, Y: t}
+			}
+		}
+		if or == nil {
+			or = and
+		} else {
+			or = &Or{X: or, Y: and}
+		}
+	}
+	return or, nil
+}
@@ -0,0 +1,56 @@
+package build
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Package is the minimal view of a parsed package Generate needs:
+// its files and, for each, the go:generate directives found in it.
+type Package struct {
+	Name  string
+	Files []*File
+}
+
+// Generate writes a shell script to w equivalent to what "go
+// generate" would run for pkg: one line per non-alias directive, in
+// file then source order, with $GOFILE and $GOPACKAGE expanded and
+// any "-command" aliases substituted in. It does not execute
+// anything itself, so callers can review or modify the script before
+// running it.
+func Generate(pkg *Package, w io.Writer) error {
+	for _, f := range pkg.Files {
+		// A "-command" alias applies only "for the remainder of this
+		// source file", per go help generate: it is reset for every
+		// file and, within a file, only takes effect for directives
+		// that follow it.
+		aliases := map[string][]string{}
+		for _, d := range f.Generate {
+			if d.IsCommandAlias {
+				if len(d.Argv) < 3 {
+					return fmt.Errorf("build: %s: -command directive needs a name and a command", f.Name)
+				}
+				aliases[d.Argv[1]] = d.Argv[2:]
+				continue
+			}
+			argv := expandArgv(d.Argv, f.Name, pkg.Name, aliases)
+			if _, err := fmt.Fprintln(w, strings.Join(argv, " ")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func expandArgv(argv []string, goFile, goPackage string, aliases map[string][]string) []string {
+	head := []string{argv[0]}
+	if repl, ok := aliases[argv[0]]; ok {
+		head = repl
+	}
+	out := append(append([]string{}, head...), argv[1:]...)
+	for i, a := range out {
+		out[i] = ExpandVars(a, goFile, goPackage)
+	}
+	return out
+}
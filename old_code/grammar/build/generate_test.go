@@ -0,0 +1,92 @@
+package build
+
+import (
+	"strings"
+	"testing"
+)
+
+func directive(t *testing.T, line string) *GenerateDirective {
+	t.Helper()
+	d, err := ParseGenerateDirective(0, line)
+	if err != nil {
+		t.Fatalf("ParseGenerateDirective(%q): %v", line, err)
+	}
+	return d
+}
+
+// TestGenerateAliasScopedToFile guards against a "-command" alias
+// defined in one file leaking into another: go help generate scopes
+// it "for the remainder of this source file only". b.go never
+// defines the "yacc" alias, so its directive must run "yacc"
+// literally rather than picking up a.go's expansion.
+func TestGenerateAliasScopedToFile(t *testing.T) {
+	pkg := &Package{
+		Name: "p",
+		Files: []*File{
+			{Name: "a.go", Generate: []*GenerateDirective{
+				directive(t, "-command yacc go run yacc.go"),
+				directive(t, "yacc -o a.out a.y"),
+			}},
+			{Name: "b.go", Generate: []*GenerateDirective{
+				directive(t, "yacc -o b.out b.y"),
+			}},
+		},
+	}
+
+	var sb strings.Builder
+	if err := Generate(pkg, &sb); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	want := "go run yacc.go -o a.out a.y\nyacc -o b.out b.y\n"
+	if sb.String() != want {
+		t.Errorf("Generate output = %q, want %q (alias from a.go must not apply to b.go)", sb.String(), want)
+	}
+}
+
+// TestGenerateAliasOrderedWithinFile guards against a directive using
+// an alias defined later in the same file: go generate processes
+// directives top to bottom, so an alias only applies to directives
+// after it.
+func TestGenerateAliasOrderedWithinFile(t *testing.T) {
+	pkg := &Package{
+		Name: "p",
+		Files: []*File{
+			{Name: "a.go", Generate: []*GenerateDirective{
+				directive(t, "yacc -o a.out a.y"),
+				directive(t, "-command yacc go run yacc.go"),
+			}},
+		},
+	}
+
+	var sb strings.Builder
+	if err := Generate(pkg, &sb); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	want := "yacc -o a.out a.y\n"
+	if sb.String() != want {
+		t.Errorf("Generate output = %q, want %q (alias defined after the directive must not apply to it)", sb.String(), want)
+	}
+}
+
+// TestGenerateAliasAppliesLaterInSameFile is the positive case: an
+// alias does apply to directives that follow it in the same file.
+func TestGenerateAliasAppliesLaterInSameFile(t *testing.T) {
+	pkg := &Package{
+		Name: "p",
+		Files: []*File{
+			{Name: "a.go", Generate: []*GenerateDirective{
+				directive(t, "-command yacc go run yacc.go"),
+				directive(t, "yacc -o a.out a.y"),
+			}},
+		},
+	}
+
+	var sb strings.Builder
+	if err := Generate(pkg, &sb); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	want := "go run yacc.go -o a.out a.y\n"
+	if sb.String() != want {
+		t.Errorf("Generate output = %q, want %q", sb.String(), want)
+	}
+}
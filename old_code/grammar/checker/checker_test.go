@@ -0,0 +1,155 @@
+package checker
+
+import (
+	"strings"
+	"testing"
+)
+
+var go120 = GoVersion{Major: 1, Minor: 20}
+
+func wantRequiresGo121(t *testing.T, err error) {
+	t.Helper()
+	if err == nil {
+		t.Fatal("got nil error, want one requiring go1.21 or later")
+	}
+	if !strings.Contains(err.Error(), "requires go1.21 or later") {
+		t.Errorf("error = %q, want it to mention %q", err.Error(), "requires go1.21 or later")
+	}
+}
+
+func TestBuiltinsRequireGo121(t *testing.T) {
+	u := NewUniverse(go120)
+	for _, name := range []string{"min", "max", "clear"} {
+		if _, ok := u.Lookup(name); ok {
+			t.Errorf("Lookup(%q) under go1.20 = true, want false", name)
+		}
+	}
+
+	u121 := NewUniverse(Go121)
+	for _, name := range []string{"min", "max", "clear"} {
+		if _, ok := u121.Lookup(name); !ok {
+			t.Errorf("Lookup(%q) under go1.21 = false, want true", name)
+		}
+	}
+}
+
+func TestRangeOverIntRequiresGo121(t *testing.T) {
+	rc := &RangeClause{Vars: []string{"i"}}
+	intType := &Basic{Kind: Int, Name: "int"}
+
+	_, ok, err := CheckRangeClause(rc, intType, go120)
+	if !ok {
+		t.Fatal("CheckRangeClause: ok = false, want true (range-over-int recognized even when gated)")
+	}
+	wantRequiresGo121(t, err)
+}
+
+// TestRangeOverIntRequiresGo121ReportsPosition guards against
+// RangeClause.Pos being threaded into CheckRangeClause but never
+// read: the "requires go1.21 or later" error must point at the
+// offending range clause, not just name the type.
+func TestRangeOverIntRequiresGo121ReportsPosition(t *testing.T) {
+	rc := &RangeClause{Pos: 42, Vars: []string{"i"}}
+	intType := &Basic{Kind: Int, Name: "int"}
+
+	_, _, err := CheckRangeClause(rc, intType, go120)
+	wantRequiresGo121(t, err)
+	if !strings.Contains(err.Error(), posString(rc.Pos)) {
+		t.Errorf("error = %q, want it to contain %q", err.Error(), posString(rc.Pos))
+	}
+}
+
+func TestRangeOverFuncRequiresGo121(t *testing.T) {
+	rc := &RangeClause{Vars: []string{"v"}}
+	iter := &Signature{Params: []Type{
+		&Signature{Params: []Type{&Basic{Kind: Int, Name: "int"}}, Results: []Type{&Basic{Kind: Bool, Name: "bool"}}},
+	}}
+
+	_, ok, err := CheckRangeClause(rc, iter, go120)
+	if !ok {
+		t.Fatal("CheckRangeClause: ok = false, want true (range-over-func recognized even when gated)")
+	}
+	wantRequiresGo121(t, err)
+}
+
+func TestRangeOverIntAllowedAtGo121PerIterationAtGo122(t *testing.T) {
+	rc := &RangeClause{Vars: []string{"i"}}
+	intType := &Basic{Kind: Int, Name: "int"}
+
+	res, ok, err := CheckRangeClause(rc, intType, Go121)
+	if !ok || err != nil {
+		t.Fatalf("CheckRangeClause at go1.21: ok=%v err=%v, want ok=true err=nil", ok, err)
+	}
+	if res.PerIteration {
+		t.Error("PerIteration at go1.21 = true, want false")
+	}
+
+	res, ok, err = CheckRangeClause(rc, intType, Go122)
+	if !ok || err != nil {
+		t.Fatalf("CheckRangeClause at go1.22: ok=%v err=%v, want ok=true err=nil", ok, err)
+	}
+	if !res.PerIteration {
+		t.Error("PerIteration at go1.22 = false, want true")
+	}
+}
+
+// orderedConstraint mirrors cmp.Ordered: a union of terms whose
+// underlying types differ (int vs string), so CoreType cannot collapse
+// them to a single type the way a "~int"-only constraint could.
+func orderedConstraint() *Constraint {
+	return &Constraint{Terms: []Term{
+		{Type: &Basic{Kind: Int, Name: "int"}},
+		{Type: &Basic{Kind: String, Name: "string"}},
+	}}
+}
+
+// TestInferTypeArgsDefaultsUntypedConstants reproduces Min(10, 5):
+// both arguments are untyped constants (argTypes entries nil) and the
+// Ordered-style constraint's CoreType is not ok, so inference must
+// fall back to each constant's default type to succeed.
+func TestInferTypeArgsDefaultsUntypedConstants(t *testing.T) {
+	tp := &TypeParam{Name: "T", Constraint: orderedConstraint()}
+	ref := &TypeParamRef{Name: "T"}
+	paramTypes := []Type{ref, ref}
+	argTypes := []Type{nil, nil}
+	constTypes := []Type{&Basic{Kind: Int, Name: "int"}, &Basic{Kind: Int, Name: "int"}}
+
+	got, err := InferTypeArgs([]*TypeParam{tp}, paramTypes, argTypes, constTypes)
+	if err != nil {
+		t.Fatalf("InferTypeArgs: %v", err)
+	}
+	if len(got) != 1 || got[0].String() != "int" {
+		t.Errorf("InferTypeArgs = %v, want [int]", got)
+	}
+}
+
+// TestInferTypeArgsTypedArgumentSkipsDefaulting verifies that when an
+// argument's type is already known, the constTypes defaulting pass
+// never runs (and isn't needed): a literal int argument alone still
+// infers T without any nil entries to default.
+func TestInferTypeArgsTypedArgumentSkipsDefaulting(t *testing.T) {
+	tp := &TypeParam{Name: "T", Constraint: orderedConstraint()}
+	ref := &TypeParamRef{Name: "T"}
+	intType := &Basic{Kind: Int, Name: "int"}
+
+	got, err := InferTypeArgs([]*TypeParam{tp}, []Type{ref}, []Type{intType}, nil)
+	if err != nil {
+		t.Fatalf("InferTypeArgs: %v", err)
+	}
+	if len(got) != 1 || got[0].String() != "int" {
+		t.Errorf("InferTypeArgs = %v, want [int]", got)
+	}
+}
+
+// TestInferTypeArgsStillFailsWithoutConstTypes confirms that
+// defaulting is opt-in: when the caller has no default types to offer
+// (constTypes nil), an all-untyped call against an Ordered-style
+// constraint still fails the way it always has.
+func TestInferTypeArgsStillFailsWithoutConstTypes(t *testing.T) {
+	tp := &TypeParam{Name: "T", Constraint: orderedConstraint()}
+	ref := &TypeParamRef{Name: "T"}
+
+	if _, err := InferTypeArgs([]*TypeParam{tp}, []Type{ref, ref}, []Type{nil, nil}, nil); err == nil {
+		t.Fatal("InferTypeArgs: got nil error, want failure with no argument or constraint to infer from")
+	}
+}
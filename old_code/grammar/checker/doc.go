@@ -0,0 +1,12 @@
+// Package checker implements the semantic-analysis passes that run
+// over the Go grammar's parse tree once Go 1.19 syntax alone is no
+// longer enough: language-version-gated features (range-over-int,
+// range-over-func, per-iteration loop variables) and the predeclared
+// identifiers added in Go 1.21.
+//
+// The version-gated checks in this package take a GoVersion, which
+// the caller threads in from wherever the enclosing module's "go"
+// directive was parsed (see the modfile subpackage); a zero
+// GoVersion compares below every real version, so every gated
+// feature is rejected under it.
+package checker
@@ -0,0 +1,356 @@
+package checker
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+)
+
+// TypeParamRef stands in for a type parameter inside a parameter or
+// result type expression, e.g. the T in "func Min[T Ordered](a, b T) T".
+// Unification binds it to a concrete Type.
+type TypeParamRef struct{ Name string }
+
+func (r *TypeParamRef) Underlying() Type { return r }
+func (r *TypeParamRef) String() string   { return r.Name }
+
+// Chan is a channel type; Dir records the direction when the
+// channel is restricted (<-chan T or chan<- T).
+type ChanDir int
+
+const (
+	SendRecv ChanDir = iota
+	SendOnly
+	RecvOnly
+)
+
+type Chan struct {
+	Dir  ChanDir
+	Elem Type
+}
+
+func (c *Chan) Underlying() Type { return c }
+func (c *Chan) String() string {
+	switch c.Dir {
+	case SendOnly:
+		return "chan<- " + c.Elem.String()
+	case RecvOnly:
+		return "<-chan " + c.Elem.String()
+	default:
+		return "chan " + c.Elem.String()
+	}
+}
+
+// Term is one element of a constraint's type set, e.g. "~int" (Tilde
+// true, matching any type whose underlying type is int) or a bare
+// method-only constraint's implicit "any" (Tilde false, Type nil).
+type Term struct {
+	Type  Type
+	Tilde bool
+}
+
+// Constraint is the expanded form of a generic type parameter's
+// constraint interface: the union of its Terms (empty meaning "any
+// type", i.e. no restriction) intersected with its required Methods.
+type Constraint struct {
+	Terms   []Term
+	Methods map[string]*Signature
+}
+
+// HasMethods is implemented by concrete types that carry a method
+// set, which is what constraint satisfaction consults for
+// interface-shaped constraints such as Stringer or Shape.
+type HasMethods interface {
+	Method(name string) (*Signature, bool)
+}
+
+// Satisfies reports whether t belongs to c's type set. On failure it
+// identifies what went wrong: failedTerm is the representative term
+// none of c.Terms matched (nil if c.Terms is empty), and
+// failedMethod is the first required method t is missing.
+func (c *Constraint) Satisfies(t Type) (ok bool, failedTerm *Term, failedMethod string) {
+	if len(c.Terms) > 0 {
+		matched := false
+		for _, term := range c.Terms {
+			if termMatches(term, t) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			first := c.Terms[0]
+			return false, &first, ""
+		}
+	}
+	names := make([]string, 0, len(c.Methods))
+	for name := range c.Methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		want := c.Methods[name]
+		hm, ok := t.(HasMethods)
+		if !ok {
+			return false, nil, name
+		}
+		got, has := hm.Method(name)
+		if !has || got.String() != want.String() {
+			return false, nil, name
+		}
+	}
+	return true, nil, ""
+}
+
+func termMatches(term Term, t Type) bool {
+	if term.Type == nil {
+		return true // unconstrained ("any")
+	}
+	if term.Tilde {
+		return t.Underlying().String() == term.Type.Underlying().String()
+	}
+	return t.String() == term.Type.String()
+}
+
+// CoreType returns the single type every Term in c reduces to once
+// "~" approximation is stripped, so InferTypeArgs can bind a type
+// param that no call argument mentions but whose constraint still
+// pins down a concrete type (e.g. a constraint of exactly "~int").
+// ok is false when c.Terms is empty or its terms disagree.
+func (c *Constraint) CoreType() (t Type, ok bool) {
+	if len(c.Terms) == 0 {
+		return nil, false
+	}
+	first := c.Terms[0].Type
+	if first == nil {
+		return nil, false
+	}
+	want := first.Underlying().String()
+	for _, term := range c.Terms[1:] {
+		if term.Type == nil || term.Type.Underlying().String() != want {
+			return nil, false
+		}
+	}
+	return first, true
+}
+
+// TypeParam is one generic declaration's type parameter, e.g. the
+// "T Ordered" in "func Min[T Ordered](...)".
+type TypeParam struct {
+	Pos        token.Pos
+	Name       string
+	Constraint *Constraint
+}
+
+// Instantiation is a generic declaration materialized at a concrete
+// type-arg tuple, keyed so repeated instantiations with the same
+// arguments (e.g. two calls to Min[int]) collapse to one node that
+// downstream passes such as codegen can iterate over.
+type Instantiation struct {
+	Generic  string
+	TypeArgs []Type
+}
+
+// Key uniquely identifies this instantiation among all
+// instantiations of the same generic declaration.
+func (i *Instantiation) Key() string {
+	s := i.Generic + "["
+	for j, t := range i.TypeArgs {
+		if j > 0 {
+			s += ","
+		}
+		s += t.String()
+	}
+	return s + "]"
+}
+
+// InstantiationSet deduplicates instantiations of generic decls by
+// their concrete type-arg tuple.
+type InstantiationSet struct {
+	byKey map[string]*Instantiation
+}
+
+// NewInstantiationSet returns an empty InstantiationSet.
+func NewInstantiationSet() *InstantiationSet {
+	return &InstantiationSet{byKey: map[string]*Instantiation{}}
+}
+
+// Get returns the existing Instantiation for (generic, args) if one
+// was already recorded, or records and returns a new one.
+func (s *InstantiationSet) Get(generic string, args []Type) *Instantiation {
+	inst := &Instantiation{Generic: generic, TypeArgs: args}
+	key := inst.Key()
+	if existing, ok := s.byKey[key]; ok {
+		return existing
+	}
+	s.byKey[key] = inst
+	return inst
+}
+
+// All returns every recorded instantiation, sorted by key for
+// deterministic iteration.
+func (s *InstantiationSet) All() []*Instantiation {
+	keys := make([]string, 0, len(s.byKey))
+	for k := range s.byKey {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]*Instantiation, len(keys))
+	for i, k := range keys {
+		out[i] = s.byKey[k]
+	}
+	return out
+}
+
+// unify attempts to bind the TypeParamRefs in pattern so that it
+// matches concrete, recording bindings in subst. It recurses through
+// the composite type shapes generic declarations actually use:
+// slices, maps, channels, and nested function signatures.
+func unify(pattern, concrete Type, subst map[string]Type) bool {
+	switch p := pattern.(type) {
+	case *TypeParamRef:
+		if existing, ok := subst[p.Name]; ok {
+			return existing.String() == concrete.String()
+		}
+		subst[p.Name] = concrete
+		return true
+	case *Slice:
+		c, ok := concrete.(*Slice)
+		return ok && unify(p.Elem, c.Elem, subst)
+	case *Map:
+		c, ok := concrete.(*Map)
+		return ok && unify(p.Key, c.Key, subst) && unify(p.Elem, c.Elem, subst)
+	case *Chan:
+		c, ok := concrete.(*Chan)
+		return ok && unify(p.Elem, c.Elem, subst)
+	case *Signature:
+		c, ok := concrete.(*Signature)
+		if !ok || len(p.Params) != len(c.Params) || len(p.Results) != len(c.Results) {
+			return false
+		}
+		for i := range p.Params {
+			if !unify(p.Params[i], c.Params[i], subst) {
+				return false
+			}
+		}
+		for i := range p.Results {
+			if !unify(p.Results[i], c.Results[i], subst) {
+				return false
+			}
+		}
+		return true
+	default:
+		return pattern.String() == concrete.String()
+	}
+}
+
+// InferTypeArgs infers a generic declaration's type arguments from
+// the static types of its call arguments, in typeParams order.
+// paramTypes is the declared parameter list (possibly mentioning
+// typeParams via TypeParamRef); argTypes is the caller's operand
+// types in the same positions, with a nil entry for any argument
+// whose type isn't known yet because it's an untyped constant.
+// constTypes gives the default type Go would assign each such
+// constant (e.g. int for an untyped integer literal) in the same
+// positions; it may be nil, or shorter than argTypes, for calls with
+// no untyped-constant arguments.
+//
+// It runs the two-phase algorithm cmd/compile uses: unify typed
+// operands against parameter types to seed a partial substitution,
+// then apply constraint type inference (a type param whose
+// constraint has a single core type gets bound to it even with no
+// matching argument), and repeat both phases until the substitution
+// stops growing. Repeating to a fixed point, rather than running
+// each phase once, is what lets one type param's binding unblock
+// unification for another in nested generic calls.
+//
+// If that leaves type params unbound, it runs the same two phases a
+// second time using constTypes in place of argTypes wherever argTypes
+// is nil -- mirroring how cmd/compile only defaults an untyped
+// constant's type once nothing else has pinned it down, so a call
+// like Min(10, 5) against a constraint whose terms don't share a core
+// type still infers T as int.
+func InferTypeArgs(typeParams []*TypeParam, paramTypes, argTypes, constTypes []Type) ([]Type, error) {
+	subst := map[string]Type{}
+
+	unifyPhase := func(types []Type) error {
+		for i := range paramTypes {
+			if i >= len(types) || types[i] == nil {
+				continue
+			}
+			if !unify(paramTypes[i], types[i], subst) {
+				return fmt.Errorf("checker: argument %d: cannot unify %s with %s", i, paramTypes[i], types[i])
+			}
+		}
+		return nil
+	}
+	coreTypePhase := func() {
+		for _, tp := range typeParams {
+			if _, bound := subst[tp.Name]; bound {
+				continue
+			}
+			if core, ok := tp.Constraint.CoreType(); ok {
+				subst[tp.Name] = core
+			}
+		}
+	}
+	runToFixedPoint := func(types []Type) error {
+		for {
+			before := len(subst)
+			if err := unifyPhase(types); err != nil {
+				return err
+			}
+			coreTypePhase()
+			if len(subst) == before {
+				return nil
+			}
+		}
+	}
+
+	if err := runToFixedPoint(argTypes); err != nil {
+		return nil, err
+	}
+
+	unbound := false
+	for _, tp := range typeParams {
+		if _, bound := subst[tp.Name]; !bound {
+			unbound = true
+			break
+		}
+	}
+	if unbound && len(constTypes) > 0 {
+		defaulted := make([]Type, len(argTypes))
+		for i := range argTypes {
+			if argTypes[i] != nil {
+				defaulted[i] = argTypes[i]
+			} else if i < len(constTypes) {
+				defaulted[i] = constTypes[i]
+			}
+		}
+		if err := runToFixedPoint(defaulted); err != nil {
+			return nil, err
+		}
+	}
+
+	args := make([]Type, len(typeParams))
+	for i, tp := range typeParams {
+		t, ok := subst[tp.Name]
+		if !ok {
+			return nil, fmt.Errorf("checker: %s: cannot infer %s from arguments or constraint", posString(tp.Pos), tp.Name)
+		}
+		if ok, failedTerm, failedMethod := tp.Constraint.Satisfies(t); !ok {
+			if failedMethod != "" {
+				return nil, fmt.Errorf("checker: %s does not satisfy %s (missing method %s)", t, tp.Name, failedMethod)
+			}
+			return nil, fmt.Errorf("checker: %s does not satisfy %s (no term matches %s)", t, tp.Name, failedTerm.Type)
+		}
+		args[i] = t
+	}
+	return args, nil
+}
+
+func posString(pos token.Pos) string {
+	if pos == token.NoPos {
+		return "<unknown position>"
+	}
+	return fmt.Sprintf("pos %d", pos)
+}
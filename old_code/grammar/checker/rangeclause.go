@@ -0,0 +1,91 @@
+package checker
+
+import (
+	"fmt"
+	"go/token"
+)
+
+// RangeClause is the subset of a "for ... range expr { ... }"
+// statement that version gating and loop-variable inference need to
+// see; the parser fills one in for every range-form for statement.
+type RangeClause struct {
+	Pos  token.Pos
+	Vars []string // 0, 1, or 2 identifiers bound by the clause
+}
+
+// RangeResult is what CheckRangeClause infers about a range clause:
+// the type bound to each identifier in Vars, in order, and whether
+// the language version in effect gives each iteration its own copy
+// of those variables.
+type RangeResult struct {
+	VarTypes     []Type
+	PerIteration bool
+}
+
+// CheckRangeClause type-checks a range clause ranging over a value
+// of type exprType under the given language version. It accepts the
+// classic array/slice/map/channel/string forms (via the ok result
+// below) as well as the Go 1.21+ range-over-integer and
+// range-over-func forms.
+//
+// ok is false when exprType is none of the range-over-integer or
+// range-over-func forms, meaning the caller should fall back to its
+// existing handling for arrays, slices, maps, channels, and strings.
+func CheckRangeClause(rc *RangeClause, exprType Type, version GoVersion) (result *RangeResult, ok bool, err error) {
+	switch t := exprType.Underlying().(type) {
+	case *Basic:
+		if !t.IsInteger() {
+			return nil, false, nil
+		}
+		if !version.AtLeast(Go121) {
+			return nil, true, fmt.Errorf("checker: %s: range over %s requires go1.21 or later", posString(rc.Pos), exprType)
+		}
+		if len(rc.Vars) > 1 {
+			return nil, true, fmt.Errorf("checker: range over %s permits at most one iteration variable", exprType)
+		}
+		return &RangeResult{
+			VarTypes:     []Type{exprType},
+			PerIteration: version.AtLeast(Go122),
+		}, true, nil
+
+	case *Signature:
+		yield, yerr := yieldSignature(t)
+		if yerr != nil {
+			return nil, false, nil
+		}
+		if !version.AtLeast(Go121) {
+			return nil, true, fmt.Errorf("checker: %s: range over %s requires go1.21 or later", posString(rc.Pos), exprType)
+		}
+		if len(rc.Vars) > len(yield.Params) {
+			return nil, true, fmt.Errorf("checker: range over %s: too many iteration variables", exprType)
+		}
+		return &RangeResult{
+			VarTypes:     yield.Params[:len(rc.Vars)],
+			PerIteration: version.AtLeast(Go122),
+		}, true, nil
+	}
+	return nil, false, nil
+}
+
+// yieldSignature recognizes the three range-over-func shapes:
+// func(func() bool), func(func(V) bool), and func(func(K, V) bool),
+// returning the inner yield function's signature.
+func yieldSignature(t *Signature) (*Signature, error) {
+	if len(t.Params) != 1 || len(t.Results) != 0 {
+		return nil, fmt.Errorf("not an iterator function")
+	}
+	yield, ok := t.Params[0].Underlying().(*Signature)
+	if !ok {
+		return nil, fmt.Errorf("not an iterator function")
+	}
+	if len(yield.Params) > 2 {
+		return nil, fmt.Errorf("yield function takes at most two arguments")
+	}
+	if len(yield.Results) != 1 {
+		return nil, fmt.Errorf("yield function must return bool")
+	}
+	if b, ok := yield.Results[0].Underlying().(*Basic); !ok || b.Kind != Bool {
+		return nil, fmt.Errorf("yield function must return bool")
+	}
+	return yield, nil
+}
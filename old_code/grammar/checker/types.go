@@ -0,0 +1,98 @@
+package checker
+
+import "strings"
+
+// Type is the minimal surface this package's checks need from the
+// type system; the main type checker's concrete types satisfy it.
+type Type interface {
+	Underlying() Type
+	String() string
+}
+
+// BasicKind enumerates the predeclared Go types this package needs
+// to reason about directly; it is not an exhaustive copy of
+// go/types.BasicKind.
+type BasicKind int
+
+const (
+	Invalid BasicKind = iota
+	Bool
+	Int
+	Int8
+	Int16
+	Int32
+	Int64
+	Uint
+	Uint8
+	Uint16
+	Uint32
+	Uint64
+	Uintptr
+	Float32
+	Float64
+	String
+)
+
+// Basic is a predeclared type such as int or string.
+type Basic struct {
+	Kind BasicKind
+	Name string
+}
+
+func (b *Basic) Underlying() Type { return b }
+func (b *Basic) String() string   { return b.Name }
+
+// IsInteger reports whether b is one of the integer kinds, which is
+// what matters for range-over-int.
+func (b *Basic) IsInteger() bool {
+	switch b.Kind {
+	case Int, Int8, Int16, Int32, Int64, Uint, Uint8, Uint16, Uint32, Uint64, Uintptr:
+		return true
+	}
+	return false
+}
+
+// Signature is a function type: func(params...) (results...).
+type Signature struct {
+	Params  []Type
+	Results []Type
+}
+
+func (s *Signature) Underlying() Type { return s }
+
+func (s *Signature) String() string {
+	var sb strings.Builder
+	sb.WriteString("func(")
+	for i, p := range s.Params {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(p.String())
+	}
+	sb.WriteString(")")
+	if len(s.Results) == 1 {
+		sb.WriteString(" " + s.Results[0].String())
+	} else if len(s.Results) > 1 {
+		sb.WriteString(" (")
+		for i, r := range s.Results {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(r.String())
+		}
+		sb.WriteString(")")
+	}
+	return sb.String()
+}
+
+// Map and Slice are the two composite kinds the clear builtin
+// accepts.
+type Map struct{ Key, Elem Type }
+
+func (m *Map) Underlying() Type { return m }
+func (m *Map) String() string   { return "map[" + m.Key.String() + "]" + m.Elem.String() }
+
+type Slice struct{ Elem Type }
+
+func (s *Slice) Underlying() Type { return s }
+func (s *Slice) String() string   { return "[]" + s.Elem.String() }
@@ -0,0 +1,100 @@
+package checker
+
+import "fmt"
+
+// Builtin identifies one of the predeclared functions added to the
+// universe scope in Go 1.21.
+type Builtin int
+
+const (
+	Min Builtin = iota
+	Max
+	Clear
+)
+
+func (b Builtin) String() string {
+	switch b {
+	case Min:
+		return "min"
+	case Max:
+		return "max"
+	case Clear:
+		return "clear"
+	}
+	return "<invalid builtin>"
+}
+
+// Universe is the set of Go 1.21 predeclared identifiers available
+// for a given language version; it is empty before go1.21.
+type Universe struct {
+	version GoVersion
+}
+
+// NewUniverse returns the Go 1.21 builtin set gated by version. The
+// main type checker's universe scope should consult this in
+// addition to its existing predeclared identifiers (true, false,
+// nil, len, append, ...), which are unaffected by version.
+func NewUniverse(version GoVersion) *Universe {
+	return &Universe{version: version}
+}
+
+// Lookup reports whether name is a Go 1.21 builtin under u's
+// version, and which one.
+func (u *Universe) Lookup(name string) (Builtin, bool) {
+	if !u.version.AtLeast(Go121) {
+		return 0, false
+	}
+	switch name {
+	case "min":
+		return Min, true
+	case "max":
+		return Max, true
+	case "clear":
+		return Clear, true
+	}
+	return 0, false
+}
+
+// CheckBuiltinCall validates a call to min, max, or clear against
+// its constraint: min and max require every argument to satisfy
+// cmp.Ordered (so: be comparable with < and >, which in this
+// package's simplified model means an integer, float, or string
+// Basic), and clear requires exactly one argument of map or slice
+// type.
+func CheckBuiltinCall(b Builtin, args []Type) error {
+	switch b {
+	case Min, Max:
+		if len(args) < 1 {
+			return fmt.Errorf("checker: not enough arguments for %s", b)
+		}
+		for _, a := range args {
+			if !isOrdered(a) {
+				return fmt.Errorf("checker: %s argument %s does not satisfy cmp.Ordered", b, a)
+			}
+		}
+		return nil
+	case Clear:
+		if len(args) != 1 {
+			return fmt.Errorf("checker: clear takes exactly one argument")
+		}
+		switch args[0].Underlying().(type) {
+		case *Map, *Slice:
+			return nil
+		default:
+			return fmt.Errorf("checker: clear argument %s is not a map or slice", args[0])
+		}
+	}
+	return nil
+}
+
+func isOrdered(t Type) bool {
+	b, ok := t.Underlying().(*Basic)
+	if !ok {
+		return false
+	}
+	switch b.Kind {
+	case Int, Int8, Int16, Int32, Int64, Uint, Uint8, Uint16, Uint32, Uint64, Uintptr, Float32, Float64, String:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package checker
+
+import "fmt"
+
+// GoVersion is a "go" directive version, e.g. go1.22 parses to
+// {Major: 1, Minor: 22}. The zero value compares less than every
+// real version, so an unset GoVersion enables none of the
+// version-gated features.
+type GoVersion struct {
+	Major, Minor int
+}
+
+// ParseGoVersion parses the value of a go.mod "go" directive, such
+// as "1.21" or "1.21.0". It ignores a patch component if present,
+// since language gating only ever depends on major.minor.
+func ParseGoVersion(s string) (GoVersion, error) {
+	var v GoVersion
+	n, err := fmt.Sscanf(s, "%d.%d", &v.Major, &v.Minor)
+	if err != nil || n != 2 {
+		return GoVersion{}, fmt.Errorf("checker: invalid go version %q", s)
+	}
+	return v, nil
+}
+
+// AtLeast reports whether v is v2 or newer.
+func (v GoVersion) AtLeast(v2 GoVersion) bool {
+	if v.Major != v2.Major {
+		return v.Major > v2.Major
+	}
+	return v.Minor >= v2.Minor
+}
+
+func (v GoVersion) String() string {
+	return fmt.Sprintf("go%d.%d", v.Major, v.Minor)
+}
+
+// Go121 and Go122 are the versions that gate the features in this
+// package: min/max/clear and range-over-func require Go121; per-
+// iteration loop variable scoping requires Go122.
+var (
+	Go121 = GoVersion{Major: 1, Minor: 21}
+	Go122 = GoVersion{Major: 1, Minor: 22}
+)
@@ -0,0 +1,84 @@
+/**
+ * Go 1.21/1.22 Grammar Test Examples
+ * These examples demonstrate the language features added on top of Go 1.19
+ * that the grammar must also accept: range-over-int, range-over-func,
+ * per-iteration loop variables, and the min/max/clear builtins.
+ */
+
+package main
+
+import "fmt"
+
+// Range over an integer (Go 1.21+): n binds to the same type as the
+// range expression, here int.
+func RangeOverIntExample() {
+	for n := range 5 {
+		fmt.Printf("n = %d\n", n)
+	}
+}
+
+// Seq is a Go 1.23-style single-value iterator; included here
+// because range-over-func (the syntax, if not Seq itself) is
+// accepted starting in Go 1.21.
+type Seq[V any] func(yield func(V) bool)
+
+// Count returns an iterator over [0, n).
+func Count(n int) Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; i < n; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+// RangeOverFuncExample ranges over a function value directly.
+func RangeOverFuncExample() {
+	for v := range Count(3) {
+		fmt.Printf("v = %d\n", v)
+	}
+}
+
+// PerIterationLoopVarExample relies on Go 1.22 per-iteration loop
+// variable scoping: each closure captures its own copy of i, so the
+// goroutines send 0, 1, 2 in some order rather than three 3s.
+func PerIterationLoopVarExample() {
+	done := make(chan int, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			done <- i
+		}()
+	}
+	for j := 0; j < 3; j++ {
+		<-done
+	}
+}
+
+// BuiltinMinMaxClearExample demonstrates the Go 1.21 predeclared
+// functions min, max, and clear.
+func BuiltinMinMaxClearExample() {
+	fmt.Println(min(3, 1, 2))
+	fmt.Println(max(3, 1, 2))
+
+	m := map[string]int{"a": 1, "b": 2}
+	clear(m)
+	fmt.Println(len(m))
+
+	s := []int{1, 2, 3}
+	clear(s)
+	fmt.Println(s)
+}
+
+// Negative case: under a module whose go.mod declares "go 1.20", the
+// checker package rejects range-over-int and range-over-func with a
+// "requires go1.21 or later" error carrying the range clause's
+// position, e.g.:
+//
+//	for n := range 5 { ... }
+//	//        ^ checker: pos N: range over untyped int requires go1.21 or later
+//
+// min, max, and clear are not in the checker's Universe below go1.21,
+// so they are not predeclared there; the checker itself reports no
+// diagnostic for a call to them. Loop variables keep the pre-1.22
+// shared-variable semantics below go1.22 rather than being rejected.
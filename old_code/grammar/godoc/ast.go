@@ -0,0 +1,108 @@
+package godoc
+
+import "go/token"
+
+// Doc is the parsed form of one comment group.
+type Doc struct {
+	Blocks []Block
+	// Links holds "[text]: url" definitions collected from anywhere
+	// in the comment, keyed by the bracketed text.
+	Links map[string]string
+	// LinkOrder records the order in which Links' keys were first
+	// defined, so Format can emit them in that order instead of
+	// Go's unspecified map iteration order.
+	LinkOrder []string
+}
+
+// Block is implemented by every top-level doc-comment block.
+type Block interface {
+	Pos() token.Pos
+	block()
+}
+
+// Paragraph is a run of wrapped text, possibly containing DocLinks.
+type Paragraph struct {
+	TextPos token.Pos
+	Text    []Inline
+}
+
+// Heading is a line recognized as a section heading: a single
+// non-indented sentence with no trailing punctuation, preceded and
+// followed by a blank line.
+type Heading struct {
+	TextPos token.Pos
+	Text    string
+}
+
+// ListKind distinguishes bullet lists from numbered lists.
+type ListKind int
+
+const (
+	// BulletList items are introduced by "-", "*", or "+".
+	BulletList ListKind = iota
+	// NumberList items are introduced by "N.".
+	NumberList
+)
+
+// List is a sequence of items sharing one ListKind.
+type List struct {
+	TextPos token.Pos
+	Kind    ListKind
+	Items   []*ListItem
+	// Marker is the bullet character ('-', '*', or '+') the source
+	// used, so Format can reproduce it; zero for a NumberList, and
+	// defaulted to '-' by Format if left unset.
+	Marker byte
+}
+
+// ListItem holds the inline content of one list entry. Continuation
+// lines, indented under the item's marker, are joined into Text.
+type ListItem struct {
+	TextPos token.Pos
+	Text    []Inline
+}
+
+// Code is a run of lines indented by a tab or four spaces, rendered
+// back verbatim (including the indentation) by Format.
+type Code struct {
+	TextPos token.Pos
+	Lines   []string
+	// Indent is the original indent prefix, "\t" or "    ", that
+	// introduced each line; Format reproduces it instead of always
+	// emitting a tab. Empty (a Code built programmatically with no
+	// Indent set) defaults to "\t" in Format.
+	Indent string
+}
+
+func (p *Paragraph) Pos() token.Pos { return p.TextPos }
+func (h *Heading) Pos() token.Pos   { return h.TextPos }
+func (l *List) Pos() token.Pos      { return l.TextPos }
+func (c *Code) Pos() token.Pos      { return c.TextPos }
+
+func (*Paragraph) block() {}
+func (*Heading) block()   {}
+func (*List) block()      {}
+func (*Code) block()      {}
+
+// Inline is text carried by a Paragraph or ListItem: either plain
+// text or a DocLink.
+type Inline interface {
+	inline()
+}
+
+// Text is a run of plain text.
+type Text string
+
+// DocLink is an inline "[Ident]" or "[pkg.Ident]" reference. It is
+// distinct from a link definition ("[text]: url"), which is
+// collected into Doc.Links instead of appearing inline.
+type DocLink struct {
+	LinkPos token.Pos
+	Pkg     string // empty for a same-package reference
+	Ident   string
+	// Symbol is filled in by Resolve; nil until then.
+	Symbol *Symbol
+}
+
+func (Text) inline()     {}
+func (*DocLink) inline() {}
@@ -0,0 +1,20 @@
+// Package godoc parses the Go 1.19 doc-comment format into a structured
+// AST and formats that AST back into comment text.
+//
+// The input to Parse is the already-unwrapped text of a comment
+// group (the "//" or "/* */" markers and any common indentation
+// stripped), exactly as the main Go grammar hands comment groups to
+// consumers such as DocumentedFunction in examples/Go119_examples.go.
+// The output is a tree of Block nodes plus the set of link
+// definitions ("[text]: url") collected at document scope.
+//
+// Format is the inverse of Parse: it preserves every block's kind and
+// structure, a List's original bullet marker, a Code block's original
+// indent style, and Links' definition order, so tools can round-trip
+// a comment through the AST to make targeted edits (for example,
+// rewriting a DocLink target) without disturbing unrelated blocks.
+// It does not preserve a paragraph or list item's original line
+// wrapping -- Parse joins each into one logical line, and Format
+// does not re-wrap it -- so output is normalized rather than a
+// byte-for-byte copy of arbitrarily wrapped source.
+package godoc
@@ -0,0 +1,116 @@
+package godoc
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Format renders a Doc back into comment text. It preserves every
+// block's structure and kind, a List's bullet marker, a Code block's
+// original indent style, and Links' definition order, so a caller can
+// edit those and get a faithful rendering back out. It does not
+// preserve a paragraph or list item's original line-wrapping: Parse
+// joins a block's source lines into one logical line (trimming each
+// line and joining them with single spaces; whitespace inside a line
+// is kept as is), and Format re-wraps none of it, so the
+// exact column where a long paragraph line happened to break is not
+// reproduced.
+func Format(d *Doc) string {
+	var sb strings.Builder
+	for i, b := range d.Blocks {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		formatBlock(&sb, b)
+	}
+	if len(d.Links) > 0 {
+		if len(d.Blocks) > 0 {
+			sb.WriteString("\n\n")
+		}
+		for _, text := range linkEmitOrder(d) {
+			sb.WriteString("[" + text + "]: " + d.Links[text] + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// linkEmitOrder returns every key of d.Links in a deterministic
+// order: first d.LinkOrder (the order links were defined in, skipping
+// any key Parse didn't record there), then any remaining keys --
+// e.g. ones a caller added directly to d.Links -- sorted
+// alphabetically.
+func linkEmitOrder(d *Doc) []string {
+	seen := make(map[string]bool, len(d.LinkOrder))
+	order := make([]string, 0, len(d.Links))
+	for _, text := range d.LinkOrder {
+		if _, ok := d.Links[text]; !ok || seen[text] {
+			continue
+		}
+		seen[text] = true
+		order = append(order, text)
+	}
+	var rest []string
+	for text := range d.Links {
+		if !seen[text] {
+			rest = append(rest, text)
+		}
+	}
+	sort.Strings(rest)
+	return append(order, rest...)
+}
+
+func formatBlock(sb *strings.Builder, b Block) {
+	switch n := b.(type) {
+	case *Heading:
+		sb.WriteString(n.Text)
+	case *Paragraph:
+		sb.WriteString(formatInline(n.Text))
+	case *Code:
+		indent := n.Indent
+		if indent == "" {
+			indent = "\t"
+		}
+		for i, ln := range n.Lines {
+			if i > 0 {
+				sb.WriteByte('\n')
+			}
+			sb.WriteString(indent)
+			sb.WriteString(ln)
+		}
+	case *List:
+		for i, item := range n.Items {
+			if i > 0 {
+				sb.WriteByte('\n')
+			}
+			switch n.Kind {
+			case BulletList:
+				marker := n.Marker
+				if marker == 0 {
+					marker = '-'
+				}
+				sb.WriteString("  " + string(marker) + " ")
+			case NumberList:
+				sb.WriteString("  " + strconv.Itoa(i+1) + ". ")
+			}
+			sb.WriteString(formatInline(item.Text))
+		}
+	}
+}
+
+func formatInline(parts []Inline) string {
+	var sb strings.Builder
+	for _, p := range parts {
+		switch v := p.(type) {
+		case Text:
+			sb.WriteString(string(v))
+		case *DocLink:
+			if v.Pkg != "" {
+				sb.WriteString("[" + v.Pkg + "." + v.Ident + "]")
+			} else {
+				sb.WriteString("[" + v.Ident + "]")
+			}
+		}
+	}
+	return sb.String()
+}
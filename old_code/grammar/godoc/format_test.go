@@ -0,0 +1,47 @@
+package godoc
+
+import "testing"
+
+// TestFormatLinksDeterministic guards against the earlier bug where
+// Format ranged over d.Links directly, so the emitted "[text]: url"
+// lines came out in a different order on every call.
+func TestFormatLinksDeterministic(t *testing.T) {
+	text := "See [Foo] and [Bar].\n\n" +
+		"[Foo]: https://example.com/foo\n" +
+		"[Bar]: https://example.com/bar\n" +
+		"[Baz]: https://example.com/baz\n"
+	d := Parse(text, 0, nil)
+
+	want := Format(d)
+	for i := 0; i < 20; i++ {
+		if got := Format(d); got != want {
+			t.Fatalf("Format is nondeterministic: run %d got:\n%s\nwant:\n%s", i, got, want)
+		}
+	}
+}
+
+// TestFormatPreservesBulletMarker guards against Format hardcoding
+// "-" regardless of which of "-", "*", or "+" the source used.
+func TestFormatPreservesBulletMarker(t *testing.T) {
+	for _, marker := range []string{"-", "*", "+"} {
+		text := marker + " one\n" + marker + " two\n"
+		d := Parse(text, 0, nil)
+		got := Format(d)
+		want := "  " + marker + " one\n  " + marker + " two"
+		if got != want {
+			t.Errorf("marker %q: Format = %q, want %q", marker, got, want)
+		}
+	}
+}
+
+// TestFormatNumberListUnaffected is a control case: numbered lists
+// have no marker to preserve and should be unchanged by the fix.
+func TestFormatNumberListUnaffected(t *testing.T) {
+	text := "1. one\n2. two\n"
+	d := Parse(text, 0, nil)
+	got := Format(d)
+	want := "  1. one\n  2. two"
+	if got != want {
+		t.Errorf("Format = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,231 @@
+package godoc
+
+import (
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+var (
+	linkDefRe  = regexp.MustCompile(`^\[([^\]]+)\]:\s*(\S+)\s*$`)
+	docLinkRe  = regexp.MustCompile(`\[([A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)?)\]`)
+	bulletRe   = regexp.MustCompile(`^(-|\*|\+)\s+(.*)$`)
+	numberedRe = regexp.MustCompile(`^([0-9]+)\.\s+(.*)$`)
+)
+
+// rawLine is one line of the comment together with the byte offset,
+// relative to the text passed to Parse, of its first rune.
+type rawLine struct {
+	offset int
+	text   string
+}
+
+// Parse parses the text of a comment group (markers and common
+// indentation already stripped) into a Doc. file and offset, if
+// file is non-nil, are used to translate byte offsets within text
+// into token.Pos values matching the rest of the parse tree; callers
+// that only need the AST shape may pass a nil file.
+func Parse(text string, offset int, file *token.File) *Doc {
+	d := &Doc{Links: map[string]string{}}
+
+	var body []rawLine
+	off := offset
+	for _, ln := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(ln)
+		if m := linkDefRe.FindStringSubmatch(trimmed); m != nil {
+			if _, seen := d.Links[m[1]]; !seen {
+				d.LinkOrder = append(d.LinkOrder, m[1])
+			}
+			d.Links[m[1]] = m[2]
+		} else {
+			body = append(body, rawLine{offset: off, text: ln})
+		}
+		off += len(ln) + 1
+	}
+
+	for _, grp := range splitBlankRuns(body) {
+		d.Blocks = append(d.Blocks, parseBlock(grp, file)...)
+	}
+	return d
+}
+
+// splitBlankRuns splits lines into runs separated by one or more
+// blank lines, dropping the blank lines themselves.
+func splitBlankRuns(lines []rawLine) [][]rawLine {
+	var groups [][]rawLine
+	var cur []rawLine
+	for _, ln := range lines {
+		if strings.TrimSpace(ln.text) == "" {
+			if len(cur) > 0 {
+				groups = append(groups, cur)
+				cur = nil
+			}
+			continue
+		}
+		cur = append(cur, ln)
+	}
+	if len(cur) > 0 {
+		groups = append(groups, cur)
+	}
+	return groups
+}
+
+func isIndented(s string) (string, bool) {
+	rest, _, ok := indentPrefix(s)
+	return rest, ok
+}
+
+// indentPrefix strips a Code block's leading indent -- a tab or four
+// spaces -- from s, also returning which of the two it was so Format
+// can reproduce the same style rather than normalizing every Code
+// block onto tabs.
+func indentPrefix(s string) (rest, prefix string, ok bool) {
+	if strings.HasPrefix(s, "\t") {
+		return s[1:], "\t", true
+	}
+	if strings.HasPrefix(s, "    ") {
+		return s[4:], "    ", true
+	}
+	return s, "", false
+}
+
+func isListLine(trimmed string) bool {
+	return bulletRe.MatchString(trimmed) || numberedRe.MatchString(trimmed)
+}
+
+// parseBlock classifies one blank-line-delimited group of lines,
+// returning more than one Block when a list follows a paragraph with
+// no blank line between them (e.g. "It supports:" immediately
+// followed by "  - Lists...") -- Go doc comments don't require a
+// blank line there, so the list has to be split out rather than
+// flattened into the paragraph's inline text.
+func parseBlock(lines []rawLine, file *token.File) []Block {
+	pos := posFor(file, lines[0].offset)
+
+	if len(lines) == 1 {
+		t := strings.TrimSpace(lines[0].text)
+		if _, indented := isIndented(lines[0].text); !indented && !isListLine(t) && isHeading(t) {
+			return []Block{&Heading{TextPos: pos, Text: t}}
+		}
+	}
+
+	if _, prefix, indented := indentPrefix(lines[0].text); indented {
+		var out []string
+		for _, ln := range lines {
+			rest, _ := isIndented(ln.text)
+			out = append(out, rest)
+		}
+		return []Block{&Code{TextPos: pos, Lines: out, Indent: prefix}}
+	}
+
+	listStart := -1
+	for i, ln := range lines {
+		if isListLine(strings.TrimSpace(ln.text)) {
+			listStart = i
+			break
+		}
+	}
+
+	switch {
+	case listStart == 0:
+		return []Block{parseList(lines, file)}
+	case listStart > 0:
+		return []Block{paragraphFromLines(lines[:listStart], pos), parseList(lines[listStart:], file)}
+	default:
+		return []Block{paragraphFromLines(lines, pos)}
+	}
+}
+
+func paragraphFromLines(lines []rawLine, pos token.Pos) *Paragraph {
+	var sb strings.Builder
+	for i, ln := range lines {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(strings.TrimSpace(ln.text))
+	}
+	return &Paragraph{TextPos: pos, Text: parseInline(sb.String(), pos)}
+}
+
+// isHeading reports whether a single line, already known to be its
+// own blank-delimited block, reads as a heading: one short sentence
+// with no trailing punctuation.
+func isHeading(line string) bool {
+	if line == "" {
+		return false
+	}
+	last := line[len(line)-1]
+	if last == '.' || last == ',' || last == ':' || last == ';' || last == '!' || last == '?' {
+		return false
+	}
+	return len(line) <= 64 && !strings.Contains(line, "  ")
+}
+
+func parseList(lines []rawLine, file *token.File) *List {
+	l := &List{TextPos: posFor(file, lines[0].offset)}
+	var items []*ListItem
+	var cur []string
+	var curPos token.Pos
+	flush := func() {
+		if cur != nil {
+			items = append(items, &ListItem{TextPos: curPos, Text: parseInline(strings.Join(cur, " "), curPos)})
+			cur = nil
+		}
+	}
+	for _, ln := range lines {
+		trimmed := strings.TrimSpace(ln.text)
+		if m := bulletRe.FindStringSubmatch(trimmed); m != nil {
+			flush()
+			l.Kind = BulletList
+			if l.Marker == 0 {
+				l.Marker = m[1][0]
+			}
+			curPos = posFor(file, ln.offset)
+			cur = []string{m[2]}
+			continue
+		}
+		if m := numberedRe.FindStringSubmatch(trimmed); m != nil {
+			flush()
+			l.Kind = NumberList
+			curPos = posFor(file, ln.offset)
+			cur = []string{m[2]}
+			continue
+		}
+		// Continuation line of the current item.
+		cur = append(cur, trimmed)
+	}
+	flush()
+	l.Items = items
+	return l
+}
+
+// parseInline splits text into Text and DocLink runs. A run inside
+// text that matches "[Ident]" or "[pkg.Ident]" becomes a DocLink;
+// everything else is Text.
+func parseInline(text string, base token.Pos) []Inline {
+	var out []Inline
+	last := 0
+	for _, m := range docLinkRe.FindAllStringSubmatchIndex(text, -1) {
+		if m[0] > last {
+			out = append(out, Text(text[last:m[0]]))
+		}
+		ref := text[m[2]:m[3]]
+		pkg, ident := "", ref
+		if i := strings.LastIndexByte(ref, '.'); i >= 0 {
+			pkg, ident = ref[:i], ref[i+1:]
+		}
+		out = append(out, &DocLink{LinkPos: base + token.Pos(m[0]), Pkg: pkg, Ident: ident})
+		last = m[1]
+	}
+	if last < len(text) {
+		out = append(out, Text(text[last:]))
+	}
+	return out
+}
+
+func posFor(file *token.File, offset int) token.Pos {
+	if file == nil {
+		return token.NoPos
+	}
+	return file.Pos(offset)
+}
@@ -0,0 +1,188 @@
+package godoc
+
+import "testing"
+
+func TestParseHeading(t *testing.T) {
+	d := Parse("Overview\n\nSome text.\n", 0, nil)
+	if len(d.Blocks) != 2 {
+		t.Fatalf("len(Blocks) = %d, want 2", len(d.Blocks))
+	}
+	h, ok := d.Blocks[0].(*Heading)
+	if !ok {
+		t.Fatalf("Blocks[0] = %T, want *Heading", d.Blocks[0])
+	}
+	if h.Text != "Overview" {
+		t.Errorf("Heading.Text = %q, want %q", h.Text, "Overview")
+	}
+	if _, ok := d.Blocks[1].(*Paragraph); !ok {
+		t.Errorf("Blocks[1] = %T, want *Paragraph", d.Blocks[1])
+	}
+}
+
+func TestParseBulletList(t *testing.T) {
+	d := Parse("- one\n- two\n", 0, nil)
+	if len(d.Blocks) != 1 {
+		t.Fatalf("len(Blocks) = %d, want 1", len(d.Blocks))
+	}
+	l, ok := d.Blocks[0].(*List)
+	if !ok {
+		t.Fatalf("Blocks[0] = %T, want *List", d.Blocks[0])
+	}
+	if l.Kind != BulletList || l.Marker != '-' {
+		t.Errorf("List = %+v, want BulletList with marker '-'", l)
+	}
+	if len(l.Items) != 2 {
+		t.Fatalf("len(Items) = %d, want 2", len(l.Items))
+	}
+}
+
+func TestParseNumberedList(t *testing.T) {
+	d := Parse("1. one\n2. two\n", 0, nil)
+	if len(d.Blocks) != 1 {
+		t.Fatalf("len(Blocks) = %d, want 1", len(d.Blocks))
+	}
+	l, ok := d.Blocks[0].(*List)
+	if !ok {
+		t.Fatalf("Blocks[0] = %T, want *List", d.Blocks[0])
+	}
+	if l.Kind != NumberList {
+		t.Errorf("Kind = %v, want NumberList", l.Kind)
+	}
+}
+
+func TestParseCodeBlockTabIndent(t *testing.T) {
+	d := Parse("\tresult := F(1)\n\tfmt.Println(result)\n", 0, nil)
+	if len(d.Blocks) != 1 {
+		t.Fatalf("len(Blocks) = %d, want 1", len(d.Blocks))
+	}
+	c, ok := d.Blocks[0].(*Code)
+	if !ok {
+		t.Fatalf("Blocks[0] = %T, want *Code", d.Blocks[0])
+	}
+	if c.Indent != "\t" {
+		t.Errorf("Indent = %q, want tab", c.Indent)
+	}
+	if len(c.Lines) != 2 || c.Lines[0] != "result := F(1)" {
+		t.Errorf("Lines = %#v", c.Lines)
+	}
+}
+
+func TestParseCodeBlockSpaceIndent(t *testing.T) {
+	d := Parse("    result := F(1)\n", 0, nil)
+	c, ok := d.Blocks[0].(*Code)
+	if !ok {
+		t.Fatalf("Blocks[0] = %T, want *Code", d.Blocks[0])
+	}
+	if c.Indent != "    " {
+		t.Errorf("Indent = %q, want four spaces", c.Indent)
+	}
+}
+
+func TestParsePlainParagraph(t *testing.T) {
+	d := Parse("This is a plain paragraph\nthat wraps onto a second line.\n", 0, nil)
+	if len(d.Blocks) != 1 {
+		t.Fatalf("len(Blocks) = %d, want 1", len(d.Blocks))
+	}
+	p, ok := d.Blocks[0].(*Paragraph)
+	if !ok {
+		t.Fatalf("Blocks[0] = %T, want *Paragraph", d.Blocks[0])
+	}
+	if len(p.Text) != 1 {
+		t.Fatalf("len(Text) = %d, want 1", len(p.Text))
+	}
+	if got, want := p.Text[0], Text("This is a plain paragraph that wraps onto a second line."); got != want {
+		t.Errorf("Text[0] = %q, want %q", got, want)
+	}
+}
+
+// TestParseListAdjacentToParagraphWithoutBlankLine guards against the
+// bug where a paragraph immediately followed by a list, with no blank
+// line between them, was classified as one flattened Paragraph and the
+// list structure lost. This is the exact shape of the
+// DocumentedFunction comment in examples/Go119_examples.go.
+func TestParseListAdjacentToParagraphWithoutBlankLine(t *testing.T) {
+	text := "It supports:\n" +
+		"  - Lists with proper formatting\n" +
+		"  - Links to other functions like [Min] and [Max]\n" +
+		"  - Code blocks and examples\n"
+	d := Parse(text, 0, nil)
+	if len(d.Blocks) != 2 {
+		t.Fatalf("len(Blocks) = %d, want 2 (intro paragraph + list): %#v", d.Blocks, d.Blocks)
+	}
+	p, ok := d.Blocks[0].(*Paragraph)
+	if !ok {
+		t.Fatalf("Blocks[0] = %T, want *Paragraph", d.Blocks[0])
+	}
+	if len(p.Text) != 1 || p.Text[0] != Text("It supports:") {
+		t.Errorf("intro paragraph Text = %#v, want [\"It supports:\"]", p.Text)
+	}
+	l, ok := d.Blocks[1].(*List)
+	if !ok {
+		t.Fatalf("Blocks[1] = %T, want *List", d.Blocks[1])
+	}
+	if len(l.Items) != 3 {
+		t.Fatalf("len(Items) = %d, want 3", len(l.Items))
+	}
+}
+
+// TestParseFormatDocumentedFunctionRoundTrip parses the exact doc
+// comment on DocumentedFunction in examples/Go119_examples.go and
+// checks that Format reproduces an equivalent comment: the "It
+// supports:" list is still a list (not flattened into the intro
+// sentence), and the indented example code is still a Code block.
+func TestParseFormatDocumentedFunctionRoundTrip(t *testing.T) {
+	text := "Documentation comments with enhanced formatting\n" +
+		"This function demonstrates the new doc comment features in Go 1.19.\n" +
+		"\n" +
+		"It supports:\n" +
+		"  - Lists with proper formatting\n" +
+		"  - Links to other functions like [Min] and [Max]\n" +
+		"  - Code blocks and examples\n" +
+		"\n" +
+		"Example usage:\n" +
+		"\n" +
+		"\tresult := DocumentedFunction(42)\n" +
+		"\tfmt.Println(result)\n" +
+		"\n" +
+		"For more information, see the Go documentation at https://go.dev/doc/\n"
+	d := Parse(text, 0, nil)
+
+	// intro paragraph, "It supports:" paragraph, list, "Example
+	// usage:" paragraph, code, trailing "For more information..."
+	// paragraph.
+	if len(d.Blocks) != 6 {
+		t.Fatalf("len(Blocks) = %d, want 6: %#v", len(d.Blocks), d.Blocks)
+	}
+	if _, ok := d.Blocks[0].(*Paragraph); !ok {
+		t.Errorf("Blocks[0] = %T, want *Paragraph", d.Blocks[0])
+	}
+	if _, ok := d.Blocks[1].(*Paragraph); !ok {
+		t.Errorf("Blocks[1] = %T, want *Paragraph (\"It supports:\")", d.Blocks[1])
+	}
+	l, ok := d.Blocks[2].(*List)
+	if !ok {
+		t.Fatalf("Blocks[2] = %T, want *List", d.Blocks[2])
+	}
+	if len(l.Items) != 3 {
+		t.Errorf("len(List.Items) = %d, want 3", len(l.Items))
+	}
+	if _, ok := d.Blocks[3].(*Paragraph); !ok {
+		t.Errorf("Blocks[3] = %T, want *Paragraph (\"Example usage:\")", d.Blocks[3])
+	}
+	code, ok := d.Blocks[4].(*Code)
+	if !ok {
+		t.Fatalf("Blocks[4] = %T, want *Code", d.Blocks[4])
+	}
+	if code.Indent != "\t" {
+		t.Errorf("Code.Indent = %q, want tab", code.Indent)
+	}
+
+	got := Format(d)
+	reparsed := Parse(got, 0, nil)
+	if len(reparsed.Blocks) != len(d.Blocks) {
+		t.Fatalf("Format output reparses to %d blocks, want %d:\n%s", len(reparsed.Blocks), len(d.Blocks), got)
+	}
+	if _, ok := reparsed.Blocks[2].(*List); !ok {
+		t.Errorf("reparsed Blocks[2] = %T, want *List -- list structure lost in round-trip", reparsed.Blocks[2])
+	}
+}
@@ -0,0 +1,72 @@
+package godoc
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+)
+
+// Symbol is the resolved target of a DocLink.
+type Symbol struct {
+	Name string
+	Pkg  string // import path, empty for the current package
+	Pos  token.Pos
+}
+
+// SymbolTable is the package symbol table produced by the main
+// parser, queried by Resolve to turn DocLink targets into Symbols.
+// A symbol table implementation typically wraps the *ast.File's
+// Scope together with the import graph needed to look up
+// cross-package references such as [fmt.Sprintf].
+type SymbolTable interface {
+	// Lookup finds ident declared in pkg (the empty string meaning
+	// the file's own package).
+	Lookup(pkg, ident string) (Symbol, bool)
+}
+
+// Diagnostic reports a DocLink that could not be resolved.
+type Diagnostic struct {
+	Pos     token.Pos
+	Message string
+}
+
+// Resolve walks every DocLink in doc, looks up its target in syms,
+// and fills in DocLink.Symbol on success. file is the *ast.File the
+// comment belongs to; Resolve does not read it, and every lookup goes
+// through syms.
+func Resolve(doc *Doc, file *ast.File, syms SymbolTable) []Diagnostic {
+	var diags []Diagnostic
+	var walkInline func([]Inline)
+	walkInline = func(parts []Inline) {
+		for _, p := range parts {
+			link, ok := p.(*DocLink)
+			if !ok {
+				continue
+			}
+			sym, ok := syms.Lookup(link.Pkg, link.Ident)
+			if !ok {
+				name := link.Ident
+				if link.Pkg != "" {
+					name = link.Pkg + "." + link.Ident
+				}
+				diags = append(diags, Diagnostic{
+					Pos:     link.LinkPos,
+					Message: fmt.Sprintf("unresolved link: %s", name),
+				})
+				continue
+			}
+			link.Symbol = &sym
+		}
+	}
+	for _, b := range doc.Blocks {
+		switch n := b.(type) {
+		case *Paragraph:
+			walkInline(n.Text)
+		case *List:
+			for _, item := range n.Items {
+				walkInline(item.Text)
+			}
+		}
+	}
+	return diags
+}
@@ -0,0 +1,73 @@
+package godoc
+
+import "testing"
+
+// stubSymbolTable resolves idents from a fixed map, keyed as
+// "pkg.Ident" (pkg empty for same-package references).
+type stubSymbolTable map[string]Symbol
+
+func (s stubSymbolTable) Lookup(pkg, ident string) (Symbol, bool) {
+	sym, ok := s[pkg+"."+ident]
+	return sym, ok
+}
+
+func TestResolveFillsSymbolOnMatch(t *testing.T) {
+	d := Parse("See [Min] for details.\n", 0, nil)
+	syms := stubSymbolTable{
+		".Min": {Name: "Min", Pos: 42},
+	}
+
+	diags := Resolve(d, nil, syms)
+	if len(diags) != 0 {
+		t.Fatalf("diags = %+v, want none", diags)
+	}
+
+	p := d.Blocks[0].(*Paragraph)
+	var link *DocLink
+	for _, part := range p.Text {
+		if l, ok := part.(*DocLink); ok {
+			link = l
+		}
+	}
+	if link == nil {
+		t.Fatalf("no DocLink found in %#v", p.Text)
+	}
+	if link.Symbol == nil || link.Symbol.Name != "Min" {
+		t.Errorf("link.Symbol = %+v, want resolved Min", link.Symbol)
+	}
+}
+
+func TestResolveReportsUnresolvedLink(t *testing.T) {
+	d := Parse("See [Missing] for details.\n", 0, nil)
+
+	diags := Resolve(d, nil, stubSymbolTable{})
+	if len(diags) != 1 {
+		t.Fatalf("len(diags) = %d, want 1: %+v", len(diags), diags)
+	}
+	if diags[0].Message != "unresolved link: Missing" {
+		t.Errorf("diags[0].Message = %q", diags[0].Message)
+	}
+
+	p := d.Blocks[0].(*Paragraph)
+	for _, part := range p.Text {
+		if l, ok := part.(*DocLink); ok && l.Symbol != nil {
+			t.Errorf("unresolved link got a Symbol: %+v", l.Symbol)
+		}
+	}
+}
+
+func TestResolveWalksListItems(t *testing.T) {
+	d := Parse("- see [Max]\n- see [Min] too\n", 0, nil)
+	syms := stubSymbolTable{".Max": {Name: "Max"}, ".Min": {Name: "Min"}}
+
+	diags := Resolve(d, nil, syms)
+	if len(diags) != 0 {
+		t.Fatalf("diags = %+v, want none", diags)
+	}
+
+	l := d.Blocks[0].(*List)
+	link := l.Items[0].Text[1].(*DocLink)
+	if link.Symbol == nil || link.Symbol.Name != "Max" {
+		t.Errorf("list item link.Symbol = %+v, want resolved Max", link.Symbol)
+	}
+}
@@ -0,0 +1,100 @@
+package incremental
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Package is the result of concurrently parsing every .go file in a
+// directory: files that parsed successfully, plus a diagnostic for
+// every file that didn't, so one syntax error doesn't abort the
+// whole walk.
+type Package struct {
+	Files       map[string]*File
+	Diagnostics map[string]error
+}
+
+// ParseDirOptions configures ParseDir.
+type ParseDirOptions struct {
+	// GOMAXPROCS caps how many files are parsed at once; zero means
+	// use runtime.GOMAXPROCS(0).
+	GOMAXPROCS int
+}
+
+// ParseDir parses every .go file directly inside dir (not
+// recursively, matching go/build's notion of one directory as one
+// package) using up to GOMAXPROCS goroutines, each calling fs.Parse.
+// ParseDir itself interns no identifiers: the files share fs.Intern
+// only where fs's Parser calls it (for example via NewGreenIdent).
+func ParseDir(fs *FileSet, dir string, opts ParseDirOptions) (*Package, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".go") {
+			names = append(names, e.Name())
+		}
+	}
+
+	procs := opts.GOMAXPROCS
+	if procs <= 0 {
+		procs = runtime.GOMAXPROCS(0)
+	}
+	if procs > len(names) {
+		procs = len(names)
+	}
+	if procs < 1 {
+		procs = 1
+	}
+
+	type result struct {
+		name string
+		file *File
+		err  error
+	}
+
+	jobs := make(chan string)
+	results := make(chan result)
+	var wg sync.WaitGroup
+	for i := 0; i < procs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range jobs {
+				path := filepath.Join(dir, name)
+				src, err := os.ReadFile(path)
+				if err != nil {
+					results <- result{name: name, err: err}
+					continue
+				}
+				f, err := fs.Parse(path, src)
+				results <- result{name: name, file: f, err: err}
+			}
+		}()
+	}
+	go func() {
+		for _, n := range names {
+			jobs <- n
+		}
+		close(jobs)
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	pkg := &Package{Files: map[string]*File{}, Diagnostics: map[string]error{}}
+	for r := range results {
+		if r.err != nil {
+			pkg.Diagnostics[r.name] = r.err
+			continue
+		}
+		pkg.Files[r.name] = r.file
+	}
+	return pkg, nil
+}
@@ -0,0 +1,16 @@
+// Package incremental adds an edit-driven parsing mode on top of the
+// Go grammar's existing single-shot parser, for editors and other
+// callers that reparse the same files over and over with small
+// changes, and a concurrent ParseDir for walking large trees once.
+//
+// The tree representation follows the red-green split common to
+// incremental parsers (Roslyn, rust-analyzer): GreenNode is an
+// immutable, offset-free node that can be shared between an old
+// parse and a Reparse of it, while RedNode is a throwaway wrapper
+// that adds the absolute offset and parent link a caller actually
+// walks the tree with. Reparse only re-parses the smallest top-level
+// declaration whose span contains an edit, splicing the resulting
+// green subtree back into an otherwise-unchanged root; it falls back
+// to a full Parse whenever an edit's span doesn't fit inside one
+// declaration.
+package incremental
@@ -0,0 +1,194 @@
+package incremental
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Parser is the from-scratch parse function the main Go grammar
+// supplies; FileSet calls it once per file and again for whatever
+// span Reparse decides needs re-parsing.
+type Parser func(src []byte) (*GreenNode, error)
+
+// Edit describes one text edit: replace the OldLen bytes starting at
+// Offset with NewText.
+type Edit struct {
+	Offset, OldLen int
+	NewText        []byte
+}
+
+func (e Edit) delta() int { return len(e.NewText) - e.OldLen }
+
+// File is one source file tracked by a FileSet.
+type File struct {
+	Path string
+	Src  []byte
+	Root *GreenNode
+}
+
+// FileSet holds the persistent state -- the file table and the
+// shared identifier interner -- that makes Reparse cheaper than
+// calling Parse again from scratch.
+type FileSet struct {
+	mu        sync.Mutex
+	files     map[string]*File
+	parseFunc Parser
+	Intern    *Interner
+}
+
+// NewFileSet returns a FileSet that uses parse as its from-scratch
+// parser.
+func NewFileSet(parse Parser) *FileSet {
+	return &FileSet{
+		files:     map[string]*File{},
+		parseFunc: parse,
+		Intern:    NewInterner(),
+	}
+}
+
+// Parse parses src as path from scratch and records it for future
+// Reparse calls.
+func (fs *FileSet) Parse(path string, src []byte) (*File, error) {
+	root, err := fs.parseFunc(src)
+	if err != nil {
+		return nil, err
+	}
+	f := &File{Path: path, Src: src, Root: root}
+	fs.mu.Lock()
+	fs.files[path] = f
+	fs.mu.Unlock()
+	return f, nil
+}
+
+// Reparse applies edits, given in original-file offsets and in
+// ascending, non-overlapping order, to the file previously parsed as
+// path. For each edit it re-parses only the smallest top-level
+// declaration whose span contains the edit and splices the result
+// into an otherwise-unchanged copy of the old tree; an edit that
+// doesn't fit inside a single top-level declaration (it spans a
+// top-level brace, or touches the leading package/import header)
+// instead triggers a full Parse of the whole file.
+func (fs *FileSet) Reparse(path string, edits []Edit) (*File, error) {
+	fs.mu.Lock()
+	old, ok := fs.files[path]
+	fs.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("incremental: %s: no prior parse to reparse from", path)
+	}
+
+	// buf tracks the source as edits are applied one at a time, so
+	// that once an edit has changed the file's length every later
+	// edit's declSrc is sliced from the up-to-date buffer rather
+	// than from stale, now-mis-sized offsets into the original
+	// source. root is kept in lockstep: findEnclosingChild always
+	// sees the tree as it stands after every splice so far, which is
+	// what makes shift -- the cumulative length delta from earlier
+	// edits -- the right adjustment for translating each edit's
+	// original-file offset into both buf's and root's current
+	// coordinates.
+	buf := old.Src
+	root := old.Root
+	shift := 0
+	fallback := false
+
+	for _, e := range edits {
+		shifted := Edit{Offset: e.Offset + shift, OldLen: e.OldLen, NewText: e.NewText}
+
+		if !fallback {
+			idx, childOffset, child := findEnclosingChild(root, shifted)
+			if child == nil {
+				fallback = true
+			} else {
+				declEnd := childOffset + child.Width
+				declSrc := buf[childOffset:declEnd]
+				newDeclSrc, err := applyEdits(declSrc, []Edit{{
+					Offset:  shifted.Offset - childOffset,
+					OldLen:  shifted.OldLen,
+					NewText: shifted.NewText,
+				}})
+				if err != nil {
+					return nil, fmt.Errorf("incremental: %s: %w", path, err)
+				}
+				newChild, err := fs.parseFunc(newDeclSrc)
+				if err != nil {
+					return nil, err
+				}
+				root = spliceChild(root, idx, newChild)
+			}
+		}
+
+		newBuf, err := applyEdits(buf, []Edit{shifted})
+		if err != nil {
+			return nil, fmt.Errorf("incremental: %s: %w", path, err)
+		}
+		buf = newBuf
+		shift += e.delta()
+	}
+
+	if fallback {
+		return fs.Parse(path, buf)
+	}
+
+	f := &File{Path: path, Src: buf, Root: root}
+	fs.mu.Lock()
+	fs.files[path] = f
+	fs.mu.Unlock()
+	return f, nil
+}
+
+// applyEdits returns src with each edit applied. Every edit's Offset
+// is interpreted against the original src, not against the
+// partially-edited buffer; applyEdits itself tracks the cumulative
+// shift in length from earlier edits so later ones still land in the
+// right place.
+func applyEdits(src []byte, edits []Edit) ([]byte, error) {
+	out := append([]byte{}, src...)
+	shift := 0
+	for _, e := range edits {
+		start := e.Offset + shift
+		end := start + e.OldLen
+		if start < 0 || end < start || end > len(out) {
+			return nil, fmt.Errorf("edit %+v out of range for %d-byte buffer", e, len(out))
+		}
+		rest := append([]byte{}, out[end:]...)
+		out = append(out[:start:start], append(append([]byte{}, e.NewText...), rest...)...)
+		shift += e.delta()
+	}
+	return out, nil
+}
+
+// findEnclosingChild locates the top-level child of root whose span
+// strictly contains e's edited range, excluding index 0
+// (conventionally the package/import header, which Reparse always
+// treats as part of a full-file reparse since it can shift every
+// later declaration's semantics). A zero-length edit sitting exactly
+// on a child's boundary is deliberately treated as not contained by
+// either neighbor -- it's ambiguous whether an insertion there
+// belongs to the declaration before or after it -- so it falls back
+// to a full reparse along with every other edit that doesn't fit
+// inside a single top-level child.
+func findEnclosingChild(root *GreenNode, e Edit) (idx int, offset int, child *GreenNode) {
+	off := 0
+	editEnd := e.Offset + e.OldLen
+	for i, c := range root.Children {
+		start, end := off, off+c.Width
+		if i > 0 && e.Offset >= start && editEnd <= end {
+			if e.OldLen == 0 && (e.Offset == start || e.Offset == end) {
+				off = end
+				continue
+			}
+			return i, start, c
+		}
+		off = end
+	}
+	return -1, 0, nil
+}
+
+// spliceChild returns a copy of root with the child at idx replaced
+// by newChild; every other child, and everything below it, is
+// reused unchanged.
+func spliceChild(root *GreenNode, idx int, newChild *GreenNode) *GreenNode {
+	children := append([]*GreenNode{}, root.Children...)
+	children[idx] = newChild
+	return NewGreenTree(root.Kind, children)
+}
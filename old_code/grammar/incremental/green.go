@@ -0,0 +1,44 @@
+package incremental
+
+// NodeKind identifies a green node's syntactic kind. It stands in
+// here for the main grammar's real token/production kind enum.
+type NodeKind int
+
+// GreenNode is an immutable syntax tree node carrying only its kind,
+// byte width, and children (or, for a token, its literal text) --
+// no parent pointer and no absolute offset. Because a GreenNode
+// never changes once built, the same *GreenNode can be a child of
+// two different trees at once, which is how Reparse reuses every
+// subtree an edit didn't touch.
+type GreenNode struct {
+	Kind     NodeKind
+	Width    int
+	Text     string       // non-empty only for leaf (token) nodes
+	Children []*GreenNode // empty for leaf nodes
+}
+
+// NewGreenToken builds a leaf green node from its literal text.
+func NewGreenToken(kind NodeKind, text string) *GreenNode {
+	return &GreenNode{Kind: kind, Width: len(text), Text: text}
+}
+
+// NewGreenIdent builds a leaf green node for an identifier token,
+// interning its text so that repeated identifiers across a FileSet
+// share one backing string.
+func NewGreenIdent(in *Interner, kind NodeKind, text string) *GreenNode {
+	return NewGreenToken(kind, in.Intern(text))
+}
+
+// NewGreenTree builds an interior green node from its children,
+// computing Width as their sum.
+func NewGreenTree(kind NodeKind, children []*GreenNode) *GreenNode {
+	width := 0
+	for _, c := range children {
+		width += c.Width
+	}
+	return &GreenNode{Kind: kind, Width: width, Children: children}
+}
+
+// IsLeaf reports whether this node is a token rather than a
+// production.
+func (g *GreenNode) IsLeaf() bool { return len(g.Children) == 0 }
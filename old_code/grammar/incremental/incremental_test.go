@@ -0,0 +1,242 @@
+package incremental
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// testParse is a from-scratch parser used only by this test file. It
+// knows nothing about Go syntax: it treats src's first
+// blank-line-separated block as the file header (index 0, matching
+// findEnclosingChild's convention) and every later block as one
+// top-level declaration, represented as a leaf node. That's enough
+// structure to exercise Reparse's splicing without dragging in a real
+// grammar.
+func testParse(src []byte) (*GreenNode, error) {
+	children := []*GreenNode{}
+	start := 0
+	for start <= len(src) {
+		end := bytes.Index(src[start:], []byte("\n\n"))
+		if end < 0 {
+			children = append(children, NewGreenToken(0, string(src[start:])))
+			break
+		}
+		end += start
+		children = append(children, NewGreenToken(0, string(src[start:end+2])))
+		start = end + 2
+	}
+	return NewGreenTree(0, children), nil
+}
+
+func mustParse(t *testing.T, fs *FileSet, path string, src []byte) *File {
+	t.Helper()
+	f, err := fs.Parse(path, src)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", path, err)
+	}
+	return f
+}
+
+// TestReparseAcrossSiblingDecls reproduces the panic reported against
+// an earlier version of Reparse: two edits landing in two different
+// top-level declarations, where the first edit lengthens the file,
+// used to make the second edit's declSrc slice index stale offsets
+// into the original (now too-short) source.
+func TestReparseAcrossSiblingDecls(t *testing.T) {
+	src := []byte("package p\n\nfunc A() {}\n\nfunc B() {}\n")
+	fs := NewFileSet(testParse)
+	mustParse(t, fs, "x", src)
+
+	aOff := bytes.Index(src, []byte("A() {}"))
+	bOff := bytes.Index(src, []byte("B() {}"))
+	if aOff < 0 || bOff < 0 || aOff >= bOff {
+		t.Fatalf("test fixture offsets wrong: aOff=%d bOff=%d", aOff, bOff)
+	}
+
+	edits := []Edit{
+		{Offset: aOff + len("A() {"), OldLen: 0, NewText: []byte("/*A*/")},
+		{Offset: bOff + len("B() {"), OldLen: 0, NewText: []byte("/*B*/")},
+	}
+
+	f, err := fs.Reparse("x", edits)
+	if err != nil {
+		t.Fatalf("Reparse: %v", err)
+	}
+	if !bytes.Contains(f.Src, []byte("A() {/*A*/}")) {
+		t.Errorf("Src missing edit to A: %s", f.Src)
+	}
+	if !bytes.Contains(f.Src, []byte("B() {/*B*/}")) {
+		t.Errorf("Src missing edit to B: %s", f.Src)
+	}
+	if len(f.Root.Children) != 3 {
+		t.Errorf("Root.Children = %d, want 3", len(f.Root.Children))
+	}
+}
+
+// TestReparseBoundaryInsertionFallsBack is a regression test for the
+// earlier fix to findEnclosingChild: a zero-length edit sitting
+// exactly on a declaration boundary is ambiguous, so Reparse must
+// fall back to a full Parse instead of guessing which neighbor it
+// belongs to.
+func TestReparseBoundaryInsertionFallsBack(t *testing.T) {
+	src := []byte("package p\n\nfunc A() {}\n\nfunc B() {}\n")
+	fs := NewFileSet(testParse)
+	mustParse(t, fs, "x", src)
+
+	boundary := bytes.Index(src, []byte("func B"))
+	f, err := fs.Reparse("x", []Edit{{Offset: boundary, OldLen: 0, NewText: []byte("func C() {}\n\n")}})
+	if err != nil {
+		t.Fatalf("Reparse: %v", err)
+	}
+	if !bytes.Contains(f.Src, []byte("func C() {}")) {
+		t.Errorf("Src missing inserted decl: %s", f.Src)
+	}
+	if len(f.Root.Children) != 4 {
+		t.Errorf("Root.Children = %d, want 4 after fallback re-split", len(f.Root.Children))
+	}
+}
+
+// wholeFileParser wraps go/parser so the benchmarks below reparse
+// something closer to real Go source. Reparse calls it twice over: once
+// on the whole file, where it splits source into a header child (the
+// package clause and imports, index 0) plus one leaf child per
+// top-level declaration; and once on a single declaration's snippet
+// when splicing one decl back in, where it wraps the snippet in a
+// synthetic "package p\n" header only to check it still parses,
+// discards that wrapper, and returns the snippet as a single leaf node
+// sized to the original (unwrapped) input.
+func wholeFileParser(src []byte) (*GreenNode, error) {
+	if _, err := parser.ParseFile(token.NewFileSet(), "", src, parser.PackageClauseOnly); err == nil {
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		tf := fset.File(file.Package)
+
+		first := 0
+		for first < len(file.Decls) {
+			gd, ok := file.Decls[first].(*ast.GenDecl)
+			if !ok || gd.Tok != token.IMPORT {
+				break
+			}
+			first++
+		}
+		headerEnd := len(src)
+		if first < len(file.Decls) {
+			headerEnd = tf.Offset(file.Decls[first].Pos())
+		}
+		children := []*GreenNode{NewGreenToken(0, string(src[:headerEnd]))}
+		for i := first; i < len(file.Decls); i++ {
+			d := file.Decls[i]
+			start := tf.Offset(d.Pos())
+			end := len(src)
+			if i+1 < len(file.Decls) {
+				end = tf.Offset(file.Decls[i+1].Pos())
+			}
+			children = append(children, NewGreenToken(0, string(src[start:end])))
+		}
+		return NewGreenTree(0, children), nil
+	}
+
+	wrapped := append([]byte("package p\n"), src...)
+	fset := token.NewFileSet()
+	if _, err := parser.ParseFile(fset, "", wrapped, parser.ParseComments); err != nil {
+		return nil, err
+	}
+	return &GreenNode{Kind: 0, Width: len(src), Text: string(src)}, nil
+}
+
+// stdlibFiles returns the absolute paths of the .go files in
+// GOROOT/src/strings, skipping the benchmark if the local toolchain
+// doesn't have a usable GOROOT checkout.
+func stdlibFiles(tb testing.TB) []string {
+	tb.Helper()
+	dir := filepath.Join(runtime.GOROOT(), "src", "strings")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		tb.Skipf("no GOROOT/src/strings available: %v", err)
+	}
+	var paths []string
+	for _, e := range entries {
+		if !e.IsDir() && filepath.Ext(e.Name()) == ".go" {
+			paths = append(paths, filepath.Join(dir, e.Name()))
+		}
+	}
+	if len(paths) == 0 {
+		tb.Skip("no .go files found under GOROOT/src/strings")
+	}
+	return paths
+}
+
+// BenchmarkParseDir parses the standard library's strings package
+// concurrently, as a stand-in for a real-world directory.
+func BenchmarkParseDir(b *testing.B) {
+	stdlibFiles(b)
+	dir := filepath.Join(runtime.GOROOT(), "src", "strings")
+	for i := 0; i < b.N; i++ {
+		fs := NewFileSet(wholeFileParser)
+		if _, err := ParseDir(fs, dir, ParseDirOptions{}); err != nil {
+			b.Fatalf("ParseDir: %v", err)
+		}
+	}
+}
+
+// BenchmarkReparseSingleEdit compares reparsing one small edit with
+// Reparse's single-declaration splice against reparsing the whole
+// file from scratch, to show the incremental path pays off over the
+// single-shot parser it replaces.
+func BenchmarkReparseSingleEdit(b *testing.B) {
+	paths := stdlibFiles(b)
+	var src []byte
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			b.Fatalf("ReadFile: %v", err)
+		}
+		if _, err := wholeFileParser(data); err == nil && len(data) > 2000 {
+			src = data
+			break
+		}
+	}
+	if src == nil {
+		b.Skip("no suitable stdlib file found")
+	}
+
+	editOffset := bytes.LastIndex(src, []byte("\n}\n"))
+	if editOffset < 0 {
+		b.Skip("fixture file has no top-level closing brace to edit near")
+	}
+	edit := Edit{Offset: editOffset, OldLen: 0, NewText: []byte("\n// bench edit\n")}
+
+	b.Run("Reparse", func(b *testing.B) {
+		fs := NewFileSet(wholeFileParser)
+		if _, err := fs.Parse("bench", src); err != nil {
+			b.Fatalf("Parse: %v", err)
+		}
+		for i := 0; i < b.N; i++ {
+			if _, err := fs.Reparse("bench", []Edit{edit}); err != nil {
+				b.Fatalf("Reparse: %v", err)
+			}
+		}
+	})
+
+	b.Run("FullParse", func(b *testing.B) {
+		edited, err := applyEdits(src, []Edit{edit})
+		if err != nil {
+			b.Fatalf("applyEdits: %v", err)
+		}
+		fs := NewFileSet(wholeFileParser)
+		for i := 0; i < b.N; i++ {
+			if _, err := fs.Parse("bench", edited); err != nil {
+				b.Fatalf("Parse: %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,29 @@
+package incremental
+
+import "sync"
+
+// Interner deduplicates identifier strings across a FileSet so that
+// repeated identifiers (package names, common field and method
+// names) share one backing string instead of allocating once per
+// occurrence.
+type Interner struct {
+	mu   sync.Mutex
+	strs map[string]string
+}
+
+// NewInterner returns an empty Interner.
+func NewInterner() *Interner {
+	return &Interner{strs: map[string]string{}}
+}
+
+// Intern returns the canonical copy of s, recording s as canonical
+// the first time it's seen.
+func (in *Interner) Intern(s string) string {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if canon, ok := in.strs[s]; ok {
+		return canon
+	}
+	in.strs[s] = s
+	return s
+}
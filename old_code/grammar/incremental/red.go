@@ -0,0 +1,37 @@
+package incremental
+
+// RedNode is a lightweight wrapper around a GreenNode that adds the
+// two things a green node deliberately omits: an absolute byte
+// offset and a parent link. Red nodes are built on demand while
+// walking a tree, so producing one costs O(depth), not O(tree size).
+type RedNode struct {
+	Green  *GreenNode
+	Parent *RedNode
+	Offset int
+}
+
+// NewRedRoot wraps a green tree's root with no parent, at offset 0.
+func NewRedRoot(g *GreenNode) *RedNode {
+	return &RedNode{Green: g}
+}
+
+// End is the byte offset one past this node's last byte.
+func (r *RedNode) End() int { return r.Offset + r.Green.Width }
+
+// Children returns this node's children as RedNodes, each carrying
+// its own absolute offset and a parent link back to r.
+func (r *RedNode) Children() []*RedNode {
+	out := make([]*RedNode, len(r.Green.Children))
+	off := r.Offset
+	for i, c := range r.Green.Children {
+		out[i] = &RedNode{Green: c, Parent: r, Offset: off}
+		off += c.Width
+	}
+	return out
+}
+
+// Contains reports whether the half-open byte range [start, end)
+// lies entirely within this node's span.
+func (r *RedNode) Contains(start, end int) bool {
+	return start >= r.Offset && end <= r.End()
+}
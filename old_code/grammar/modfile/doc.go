@@ -0,0 +1,13 @@
+// Package modfile parses go.mod, go.sum, and go.work files and
+// builds the module graph that resolves their effective versions.
+//
+// Parsing preserves the comments that matter for re-serialization --
+// the inline "// indirect" marker on a require, a retract's
+// rationale, a use directive's trailing comment -- so a caller can
+// edit a parsed GoMod, GoWork, or GoSum and pass it to FormatGoMod,
+// FormatGoWork, or FormatGoSum to get it back out. Unlike
+// golang.org/x/mod/modfile, formatting here is canonical rather than
+// byte-preserving: it reproduces the directives and their comments in
+// a fixed, gofmt-like layout, not the original file's exact spacing
+// or block grouping.
+package modfile
@@ -0,0 +1,126 @@
+package modfile
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FormatGoMod renders m as go.mod text. The output is canonical --
+// one directive block per kind, gofmt-style -- rather than a
+// byte-for-byte reproduction of whatever formatting the original file
+// used; comments that ParseGoMod preserved (the "// indirect" marker
+// and retract rationales) are written back out.
+func FormatGoMod(m *GoMod) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "module %s\n", m.Module.Path)
+	b.WriteByte('\n')
+	if m.Go != "" {
+		fmt.Fprintf(&b, "go %s\n", m.Go)
+	}
+	if m.Toolchain != "" {
+		fmt.Fprintf(&b, "toolchain %s\n", m.Toolchain)
+	}
+
+	if len(m.Require) > 0 {
+		b.WriteByte('\n')
+		b.WriteString("require (\n")
+		for _, r := range m.Require {
+			fmt.Fprintf(&b, "\t%s", formatModuleSide(r.Mod))
+			if r.Comment != "" {
+				fmt.Fprintf(&b, " // %s", r.Comment)
+			}
+			b.WriteByte('\n')
+		}
+		b.WriteString(")\n")
+	}
+
+	if len(m.Exclude) > 0 {
+		b.WriteByte('\n')
+		b.WriteString("exclude (\n")
+		for _, ex := range m.Exclude {
+			fmt.Fprintf(&b, "\t%s\n", formatModuleSide(*ex))
+		}
+		b.WriteString(")\n")
+	}
+
+	if len(m.Replace) > 0 {
+		b.WriteByte('\n')
+		b.WriteString("replace (\n")
+		for _, r := range m.Replace {
+			fmt.Fprintf(&b, "\t%s => %s\n", formatModuleSide(r.Old), formatModuleSide(r.New))
+		}
+		b.WriteString(")\n")
+	}
+
+	if len(m.Retract) > 0 {
+		b.WriteByte('\n')
+		b.WriteString("retract (\n")
+		for _, r := range m.Retract {
+			if r.Low == r.High {
+				fmt.Fprintf(&b, "\t%s", r.Low)
+			} else {
+				fmt.Fprintf(&b, "\t[%s, %s]", r.Low, r.High)
+			}
+			if r.Rationale != "" {
+				fmt.Fprintf(&b, " // %s", r.Rationale)
+			}
+			b.WriteByte('\n')
+		}
+		b.WriteString(")\n")
+	}
+
+	return []byte(b.String())
+}
+
+// FormatGoWork renders w as go.work text, in the same canonical,
+// comment-preserving style as FormatGoMod.
+func FormatGoWork(w *GoWork) []byte {
+	var b strings.Builder
+	if w.Go != "" {
+		fmt.Fprintf(&b, "go %s\n", w.Go)
+	}
+
+	if len(w.Use) > 0 {
+		b.WriteByte('\n')
+		b.WriteString("use (\n")
+		for _, u := range w.Use {
+			fmt.Fprintf(&b, "\t%s", u.Path)
+			if u.Comment != "" {
+				fmt.Fprintf(&b, " // %s", u.Comment)
+			}
+			b.WriteByte('\n')
+		}
+		b.WriteString(")\n")
+	}
+
+	if len(w.Replace) > 0 {
+		b.WriteByte('\n')
+		b.WriteString("replace (\n")
+		for _, r := range w.Replace {
+			fmt.Fprintf(&b, "\t%s => %s\n", formatModuleSide(r.Old), formatModuleSide(r.New))
+		}
+		b.WriteString(")\n")
+	}
+
+	return []byte(b.String())
+}
+
+// FormatGoSum renders s as go.sum text, one record per line in the
+// order it was parsed.
+func FormatGoSum(s *GoSum) []byte {
+	var b strings.Builder
+	for _, rec := range s.Records {
+		fmt.Fprintf(&b, "%s %s %s\n", rec.Path, rec.Version, rec.Hash)
+	}
+	return []byte(b.String())
+}
+
+// formatModuleSide renders one side of a require/exclude/replace
+// directive: "path version", or just "path" for a replace target
+// that is a local filesystem path (Version == "").
+func formatModuleSide(m Module) string {
+	if m.Version == "" {
+		return m.Path
+	}
+	return m.Path + " " + m.Version
+}
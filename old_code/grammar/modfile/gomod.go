@@ -0,0 +1,187 @@
+package modfile
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Module identifies a module at a version; Version is empty for a
+// replace target that is a local filesystem path.
+type Module struct {
+	Path    string
+	Version string
+}
+
+// Require is one "require" directive.
+type Require struct {
+	Mod      Module
+	Indirect bool
+	// Comment is the trailing line comment's text with the leading
+	// "//" and surrounding spaces removed, typically "indirect",
+	// preserved so re-serialization doesn't lose it.
+	Comment string
+}
+
+// Replace is one "replace" directive: Old is replaced by New. New's
+// Version is empty when New.Path is a local filesystem path rather
+// than a module path.
+type Replace struct {
+	Old, New Module
+}
+
+// Retract is one "retract" directive; Low == High for a single
+// retracted version rather than a range.
+type Retract struct {
+	Low, High string
+	Rationale string
+}
+
+// GoMod is a parsed go.mod file.
+type GoMod struct {
+	Module    Module
+	Go        string
+	Toolchain string
+	Require   []*Require
+	Exclude   []*Module
+	Replace   []*Replace
+	Retract   []*Retract
+}
+
+// ParseGoMod parses the contents of a go.mod file. filename is used
+// only to annotate error messages.
+func ParseGoMod(filename string, data []byte) (*GoMod, error) {
+	m := &GoMod{}
+	block := ""
+	for i, raw := range strings.Split(string(data), "\n") {
+		line, comment := splitComment(raw)
+		line = strings.TrimSpace(line)
+		lineNo := i + 1
+
+		if block != "" {
+			if line == ")" {
+				block = ""
+				continue
+			}
+			if line == "" {
+				continue
+			}
+			if err := m.parseDirective(block, line, comment); err != nil {
+				return nil, fmt.Errorf("%s:%d: %v", filename, lineNo, err)
+			}
+			continue
+		}
+
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		verb := fields[0]
+		rest := strings.TrimSpace(strings.TrimPrefix(line, verb))
+
+		if rest == "(" {
+			block = verb
+			continue
+		}
+
+		if err := m.parseDirective(verb, rest, comment); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", filename, lineNo, err)
+		}
+	}
+	return m, nil
+}
+
+// splitComment splits a line into its code and "// comment" parts;
+// comment is "" if the line has none. It does not need to handle
+// string literals, since no go.mod directive contains a "//".
+func splitComment(line string) (code, comment string) {
+	if i := strings.Index(line, "//"); i >= 0 {
+		return line[:i], strings.TrimSpace(line[i+2:])
+	}
+	return line, ""
+}
+
+func (m *GoMod) parseDirective(verb, rest string, comment string) error {
+	switch verb {
+	case "module":
+		m.Module.Path = strings.Trim(rest, `"`)
+	case "go":
+		m.Go = rest
+	case "toolchain":
+		m.Toolchain = rest
+	case "require":
+		mod, err := parseModule(rest)
+		if err != nil {
+			return err
+		}
+		m.Require = append(m.Require, &Require{
+			Mod:      mod,
+			Indirect: comment == "indirect",
+			Comment:  comment,
+		})
+	case "exclude":
+		mod, err := parseModule(rest)
+		if err != nil {
+			return err
+		}
+		m.Exclude = append(m.Exclude, &mod)
+	case "replace":
+		r, err := parseReplace(rest)
+		if err != nil {
+			return err
+		}
+		m.Replace = append(m.Replace, r)
+	case "retract":
+		r, err := parseRetract(rest)
+		if err != nil {
+			return err
+		}
+		r.Rationale = comment
+		m.Retract = append(m.Retract, r)
+	default:
+		return fmt.Errorf("unknown directive %q", verb)
+	}
+	return nil
+}
+
+func parseModule(s string) (Module, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return Module{}, fmt.Errorf("malformed module line %q", s)
+	}
+	return Module{Path: fields[0], Version: fields[1]}, nil
+}
+
+func parseReplace(s string) (*Replace, error) {
+	parts := strings.SplitN(s, "=>", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("malformed replace line %q", s)
+	}
+	oldFields := strings.Fields(parts[0])
+	newFields := strings.Fields(parts[1])
+	if len(oldFields) == 0 || len(newFields) == 0 {
+		return nil, fmt.Errorf("malformed replace line %q", s)
+	}
+	r := &Replace{}
+	r.Old.Path = oldFields[0]
+	if len(oldFields) > 1 {
+		r.Old.Version = oldFields[1]
+	}
+	r.New.Path = newFields[0]
+	if len(newFields) > 1 {
+		r.New.Version = newFields[1]
+	}
+	return r, nil
+}
+
+func parseRetract(s string) (*Retract, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		inner := strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+		bounds := strings.Split(inner, ",")
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("malformed retract range %q", s)
+		}
+		return &Retract{Low: strings.TrimSpace(bounds[0]), High: strings.TrimSpace(bounds[1])}, nil
+	}
+	return &Retract{Low: s, High: s}, nil
+}
@@ -0,0 +1,38 @@
+package modfile
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SumRecord is one "module version hash" line of a go.sum file. A
+// module appears twice: once for its source tree hash (Version has
+// no suffix) and once for its go.mod file's hash (Version ends in
+// "/go.mod").
+type SumRecord struct {
+	Path    string
+	Version string
+	Hash    string
+}
+
+// GoSum is a parsed go.sum file.
+type GoSum struct {
+	Records []SumRecord
+}
+
+// ParseGoSum parses the contents of a go.sum file.
+func ParseGoSum(filename string, data []byte) (*GoSum, error) {
+	s := &GoSum{}
+	for i, raw := range strings.Split(string(data), "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("%s:%d: malformed go.sum line %q", filename, i+1, raw)
+		}
+		s.Records = append(s.Records, SumRecord{Path: fields[0], Version: fields[1], Hash: fields[2]})
+	}
+	return s, nil
+}
@@ -0,0 +1,84 @@
+package modfile
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Use is one "use" directive: a directory, relative to the go.work
+// file, of a module that is part of the workspace.
+type Use struct {
+	Path string
+	// Comment is the trailing line comment's text with the leading
+	// "//" and surrounding spaces removed, preserved so
+	// re-serialization doesn't lose it.
+	Comment string
+}
+
+// GoWork is a parsed go.work file.
+type GoWork struct {
+	Go      string
+	Use     []*Use
+	Replace []*Replace
+}
+
+// ParseGoWork parses the contents of a go.work file. Its directive
+// set is a subset of go.mod's: go, use, and replace, with the same
+// block-form and comment-preservation rules.
+func ParseGoWork(filename string, data []byte) (*GoWork, error) {
+	w := &GoWork{}
+	block := ""
+	for i, raw := range strings.Split(string(data), "\n") {
+		line, comment := splitComment(raw)
+		line = strings.TrimSpace(line)
+		lineNo := i + 1
+
+		if block != "" {
+			if line == ")" {
+				block = ""
+				continue
+			}
+			if line == "" {
+				continue
+			}
+			if err := w.parseDirective(block, line, comment); err != nil {
+				return nil, fmt.Errorf("%s:%d: %v", filename, lineNo, err)
+			}
+			continue
+		}
+
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		verb := fields[0]
+		rest := strings.TrimSpace(strings.TrimPrefix(line, verb))
+
+		if rest == "(" {
+			block = verb
+			continue
+		}
+		if err := w.parseDirective(verb, rest, comment); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", filename, lineNo, err)
+		}
+	}
+	return w, nil
+}
+
+func (w *GoWork) parseDirective(verb, rest string, comment string) error {
+	switch verb {
+	case "go":
+		w.Go = rest
+	case "use":
+		w.Use = append(w.Use, &Use{Path: strings.Trim(rest, `"`), Comment: comment})
+	case "replace":
+		r, err := parseReplace(rest)
+		if err != nil {
+			return err
+		}
+		w.Replace = append(w.Replace, r)
+	default:
+		return fmt.Errorf("unknown directive %q", verb)
+	}
+	return nil
+}
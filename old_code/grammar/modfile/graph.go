@@ -0,0 +1,187 @@
+package modfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/DevelApp-ai/Minotaur/old_code/grammar/checker"
+)
+
+// ResolvedModule is one module's effective version after use,
+// replace, and exclude rules have been applied.
+type ResolvedModule struct {
+	Module
+	// Dir is set instead of Version when a replace directive points
+	// at a local filesystem path.
+	Dir string
+}
+
+// ModuleGraph is the result of resolving a workspace's (or single
+// module's) effective module versions.
+type ModuleGraph struct {
+	Main    Module
+	Modules map[string]*ResolvedModule // module path -> resolution
+	gomods  map[string]*GoMod          // module path -> its parsed go.mod
+}
+
+// BuildModuleGraph resolves the effective module graph rooted at
+// root: if root contains a go.work file, every module it "use"s
+// contributes its requirements and the workspace's own replace
+// directives take precedence; otherwise root is treated as a single
+// module directory.
+func BuildModuleGraph(root string) (*ModuleGraph, error) {
+	g := &ModuleGraph{Modules: map[string]*ResolvedModule{}, gomods: map[string]*GoMod{}}
+
+	workPath := filepath.Join(root, "go.work")
+	var moduleDirs []string
+	var workReplaces []*Replace
+
+	if data, err := os.ReadFile(workPath); err == nil {
+		w, err := ParseGoWork(workPath, data)
+		if err != nil {
+			return nil, err
+		}
+		for _, use := range w.Use {
+			moduleDirs = append(moduleDirs, filepath.Join(root, use.Path))
+		}
+		workReplaces = w.Replace
+	} else {
+		moduleDirs = []string{root}
+	}
+
+	var gms []*GoMod
+	for i, dir := range moduleDirs {
+		gm, err := readGoMod(dir)
+		if err != nil {
+			return nil, err
+		}
+		g.gomods[gm.Module.Path] = gm
+		if i == 0 {
+			g.Main = gm.Module
+		}
+		g.Modules[gm.Module.Path] = &ResolvedModule{Module: gm.Module}
+		gms = append(gms, gm)
+	}
+
+	// Minimal version selection runs over every module's requires
+	// before any exclude or replace is applied: otherwise a module
+	// processed later could "win" a higher version for a dependency
+	// an earlier module's exclude had already ruled out, resurrecting
+	// a version that was deliberately excluded.
+	for _, gm := range gms {
+		for _, req := range gm.Require {
+			if existing, ok := g.Modules[req.Mod.Path]; ok {
+				if versionLess(existing.Version, req.Mod.Version) {
+					existing.Version = req.Mod.Version
+				}
+			} else {
+				g.Modules[req.Mod.Path] = &ResolvedModule{Module: req.Mod}
+			}
+		}
+	}
+
+	for _, gm := range gms {
+		g.applyExcludes(gm.Exclude)
+		g.applyReplaces(gm.Replace)
+	}
+
+	// Workspace-level replaces override every module's own.
+	g.applyReplaces(workReplaces)
+
+	return g, nil
+}
+
+func readGoMod(dir string) (*GoMod, error) {
+	path := filepath.Join(dir, "go.mod")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("modfile: %w", err)
+	}
+	return ParseGoMod(path, data)
+}
+
+func (g *ModuleGraph) applyExcludes(excludes []*Module) {
+	for _, ex := range excludes {
+		if rm, ok := g.Modules[ex.Path]; ok && rm.Version == ex.Version {
+			// The excluded version cannot be selected; without a
+			// module proxy to ask for the next-highest version,
+			// mark it unresolved rather than guess.
+			rm.Version = ""
+		}
+	}
+}
+
+func (g *ModuleGraph) applyReplaces(replaces []*Replace) {
+	for _, r := range replaces {
+		rm, ok := g.Modules[r.Old.Path]
+		if !ok {
+			rm = &ResolvedModule{Module: r.Old}
+			g.Modules[r.Old.Path] = rm
+		}
+		if r.Old.Version != "" && rm.Version != r.Old.Version {
+			continue
+		}
+		if r.New.Version == "" {
+			rm.Dir = r.New.Path
+			rm.Version = ""
+		} else {
+			rm.Module = r.New
+			rm.Dir = ""
+		}
+	}
+}
+
+// versionLess reports whether a sorts before b under a best-effort,
+// MVS-style version comparison: numeric major.minor.patch first, then
+// a lexical comparison of any "-" or "+" suffix (pre-release/build
+// metadata). It is not a full semver precedence implementation --
+// just enough to pick the higher of two require versions for the
+// same module path.
+func versionLess(a, b string) bool {
+	if a == "" {
+		return b != ""
+	}
+	if b == "" {
+		return false
+	}
+	an, asuf := splitVersion(a)
+	bn, bsuf := splitVersion(b)
+	for i := range an {
+		if an[i] != bn[i] {
+			return an[i] < bn[i]
+		}
+	}
+	return asuf < bsuf
+}
+
+// splitVersion splits a version like "v1.2.3-pre" into its numeric
+// major.minor.patch parts and any trailing "-"/"+" suffix. Missing or
+// non-numeric parts are treated as zero.
+func splitVersion(v string) ([3]int, string) {
+	v = strings.TrimPrefix(v, "v")
+	var suffix string
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		suffix = v[i:]
+		v = v[:i]
+	}
+	var nums [3]int
+	for i, part := range strings.SplitN(v, ".", 3) {
+		n, _ := strconv.Atoi(part)
+		nums[i] = n
+	}
+	return nums, suffix
+}
+
+// GoVersion returns the parsed "go" directive version declared by
+// modulePath's own go.mod, so the source parser can gate language
+// features per module rather than for the whole graph at once.
+func (g *ModuleGraph) GoVersion(modulePath string) (checker.GoVersion, error) {
+	gm, ok := g.gomods[modulePath]
+	if !ok {
+		return checker.GoVersion{}, fmt.Errorf("modfile: no go.mod loaded for module %q", modulePath)
+	}
+	return checker.ParseGoVersion(gm.Go)
+}
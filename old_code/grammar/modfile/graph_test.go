@@ -0,0 +1,240 @@
+package modfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
+
+func TestParseGoModRetractBlock(t *testing.T) {
+	data := []byte(`module example.com/m
+
+go 1.21
+
+retract (
+	v1.0.0 // published by mistake
+	[v1.1.0, v1.2.0] // broken API
+)
+`)
+	gm, err := ParseGoMod("go.mod", data)
+	if err != nil {
+		t.Fatalf("ParseGoMod: %v", err)
+	}
+	if len(gm.Retract) != 2 {
+		t.Fatalf("len(Retract) = %d, want 2", len(gm.Retract))
+	}
+	if gm.Retract[0].Low != "v1.0.0" || gm.Retract[0].High != "v1.0.0" {
+		t.Errorf("Retract[0] = %+v, want single v1.0.0", gm.Retract[0])
+	}
+	if gm.Retract[0].Rationale != "published by mistake" {
+		t.Errorf("Retract[0].Rationale = %q", gm.Retract[0].Rationale)
+	}
+	if gm.Retract[1].Low != "v1.1.0" || gm.Retract[1].High != "v1.2.0" {
+		t.Errorf("Retract[1] = %+v, want range v1.1.0..v1.2.0", gm.Retract[1])
+	}
+	if gm.Retract[1].Rationale != "broken API" {
+		t.Errorf("Retract[1].Rationale = %q", gm.Retract[1].Rationale)
+	}
+}
+
+func TestBuildModuleGraphReplaceWithLocalPath(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "go.mod", `module example.com/m
+
+go 1.21
+
+require example.com/dep v1.0.0
+
+replace example.com/dep => ./dep
+`)
+
+	g, err := BuildModuleGraph(dir)
+	if err != nil {
+		t.Fatalf("BuildModuleGraph: %v", err)
+	}
+	rm, ok := g.Modules["example.com/dep"]
+	if !ok {
+		t.Fatalf("no resolution for example.com/dep")
+	}
+	if rm.Dir != "./dep" {
+		t.Errorf("Dir = %q, want %q", rm.Dir, "./dep")
+	}
+	if rm.Version != "" {
+		t.Errorf("Version = %q, want empty for local-path replace", rm.Version)
+	}
+}
+
+func TestBuildModuleGraphMultiModuleWorkspace(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "go.work", `go 1.22
+
+use (
+	./mod1
+	./mod2
+)
+`)
+	writeFile(t, dir, "mod1/go.mod", `module example.com/mod1
+
+go 1.21
+
+require example.com/shared v1.0.0
+`)
+	writeFile(t, dir, "mod2/go.mod", `module example.com/mod2
+
+go 1.22
+
+require example.com/shared v1.2.0
+`)
+
+	g, err := BuildModuleGraph(dir)
+	if err != nil {
+		t.Fatalf("BuildModuleGraph: %v", err)
+	}
+	if g.Main.Path != "example.com/mod1" {
+		t.Errorf("Main.Path = %q, want example.com/mod1 (first use entry)", g.Main.Path)
+	}
+	shared, ok := g.Modules["example.com/shared"]
+	if !ok {
+		t.Fatalf("no resolution for example.com/shared")
+	}
+	if shared.Version != "v1.2.0" {
+		t.Errorf("shared.Version = %q, want v1.2.0 (max-version selection across modules)", shared.Version)
+	}
+
+	v1, err := g.GoVersion("example.com/mod1")
+	if err != nil || v1.Minor != 21 {
+		t.Errorf("GoVersion(mod1) = %+v, %v, want Minor 21", v1, err)
+	}
+	v2, err := g.GoVersion("example.com/mod2")
+	if err != nil || v2.Minor != 22 {
+		t.Errorf("GoVersion(mod2) = %+v, %v, want Minor 22", v2, err)
+	}
+}
+
+// TestBuildModuleGraphExcludeSurvivesLaterRequire guards against
+// minimal version selection undoing an exclude: mod1 requires and
+// excludes example.com/shared v1.0.0, and mod2 also requires
+// example.com/shared v1.0.0. Merging every module's requires before
+// any exclude is applied must leave the exclude in place rather than
+// having mod2's requirement "win" the excluded version back.
+func TestBuildModuleGraphExcludeSurvivesLaterRequire(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "go.work", `go 1.22
+
+use (
+	./mod1
+	./mod2
+)
+`)
+	writeFile(t, dir, "mod1/go.mod", `module example.com/mod1
+
+go 1.21
+
+require example.com/shared v1.0.0
+
+exclude example.com/shared v1.0.0
+`)
+	writeFile(t, dir, "mod2/go.mod", `module example.com/mod2
+
+go 1.21
+
+require example.com/shared v1.0.0
+`)
+
+	g, err := BuildModuleGraph(dir)
+	if err != nil {
+		t.Fatalf("BuildModuleGraph: %v", err)
+	}
+	shared, ok := g.Modules["example.com/shared"]
+	if !ok {
+		t.Fatalf("no resolution for example.com/shared")
+	}
+	if shared.Version != "" {
+		t.Errorf("shared.Version = %q, want empty: the excluded version must stay unresolved", shared.Version)
+	}
+}
+
+func TestVersionLess(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want bool
+	}{
+		{"v1.0.0", "v1.2.0", true},
+		{"v1.2.0", "v1.0.0", false},
+		{"v1.2.0", "v1.2.0", false},
+		{"", "v1.0.0", true},
+		{"v1.0.0", "", false},
+		{"v1.2.3", "v1.2.10", true},
+	}
+	for _, c := range cases {
+		if got := versionLess(c.a, c.b); got != c.want {
+			t.Errorf("versionLess(%q, %q) = %v, want %v", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestFormatGoModRoundTripsDirectives(t *testing.T) {
+	data := []byte(`module example.com/m
+
+go 1.21
+
+require (
+	example.com/a v1.0.0
+	example.com/b v1.1.0 // indirect
+)
+
+retract v1.0.0 // bad release
+`)
+	gm, err := ParseGoMod("go.mod", data)
+	if err != nil {
+		t.Fatalf("ParseGoMod: %v", err)
+	}
+	reparsed, err := ParseGoMod("go.mod", FormatGoMod(gm))
+	if err != nil {
+		t.Fatalf("ParseGoMod(FormatGoMod(gm)): %v", err)
+	}
+	if reparsed.Module.Path != gm.Module.Path || reparsed.Go != gm.Go {
+		t.Errorf("module/go directive lost in round-trip: %+v", reparsed)
+	}
+	if len(reparsed.Require) != 2 || !reparsed.Require[1].Indirect {
+		t.Errorf("require directives lost in round-trip: %+v", reparsed.Require)
+	}
+	if len(reparsed.Retract) != 1 || reparsed.Retract[0].Rationale != "bad release" {
+		t.Errorf("retract rationale lost in round-trip: %+v", reparsed.Retract)
+	}
+}
+
+func TestFormatGoWorkPreservesUseComment(t *testing.T) {
+	data := []byte(`go 1.22
+
+use (
+	./mod1 // primary module
+	./mod2
+)
+`)
+	w, err := ParseGoWork("go.work", data)
+	if err != nil {
+		t.Fatalf("ParseGoWork: %v", err)
+	}
+	if w.Use[0].Comment != "primary module" {
+		t.Fatalf("Use[0].Comment = %q, want %q", w.Use[0].Comment, "primary module")
+	}
+	reparsed, err := ParseGoWork("go.work", FormatGoWork(w))
+	if err != nil {
+		t.Fatalf("ParseGoWork(FormatGoWork(w)): %v", err)
+	}
+	if len(reparsed.Use) != 2 || reparsed.Use[0].Comment != "primary module" {
+		t.Errorf("use comment lost in round-trip: %+v", reparsed.Use)
+	}
+}